@@ -0,0 +1,104 @@
+package rng
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// PoissonArrivals returns an infinite sequence of arrival times of a homogeneous Poisson process
+// with the given rate in events per second.
+//
+// Each yielded value is the offset of an arrival from the start of the process, so the values
+// are non-decreasing. The sequence is empty if rate <= 0.
+func PoissonArrivals(rate float64) iter.Seq[time.Duration] {
+	return func(yield func(time.Duration) bool) {
+		if !(rate > 0) {
+			return
+		}
+		t := 0.0
+		for {
+			t += r.ExpFloat64() / rate
+			if !yield(seconds(t)) {
+				return
+			}
+		}
+	}
+}
+
+// NonHomogeneousPoissonArrivals returns an infinite sequence of arrival times of a Poisson process
+// whose rate in events per second varies over time.
+//
+// Arrivals are generated by thinning: candidates are drawn at maxRate and each one is kept with
+// probability rate(t)/maxRate, where t is the candidate's offset from the start of the process.
+// maxRate must be an upper bound of rate; values of rate above it are treated as maxRate.
+// The sequence is empty if maxRate <= 0.
+func NonHomogeneousPoissonArrivals(rate func(t time.Duration) float64, maxRate float64) iter.Seq[time.Duration] {
+	return func(yield func(time.Duration) bool) {
+		for t := range PoissonArrivals(maxRate) {
+			if r.Float64()*maxRate >= rate(t) {
+				continue
+			}
+			if !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// RenewalArrivals returns an infinite sequence of arrival times of a renewal process whose
+// inter-arrival times in seconds are drawn from the given distribution.
+//
+// Each yielded value is the offset of an arrival from the start of the process.
+// Negative inter-arrival samples are treated as 0.
+func RenewalArrivals(interArrival Distribution) iter.Seq[time.Duration] {
+	return func(yield func(time.Duration) bool) {
+		t := 0.0
+		for {
+			t += max(interArrival.Sample(), 0)
+			if !yield(seconds(t)) {
+				return
+			}
+		}
+	}
+}
+
+// ArrivalChan replays a sequence of arrival offsets in real time.
+//
+// It starts a goroutine that sends the current time on the returned channel as each arrival
+// becomes due, measured from the moment ArrivalChan was called. The channel is unbuffered, so
+// arrivals that are not received in time are delivered late rather than dropped.
+// The channel is closed when the sequence ends or ctx is canceled.
+func ArrivalChan(ctx context.Context, arrivals iter.Seq[time.Duration]) <-chan time.Time {
+	ch := make(chan time.Time)
+	start := time.Now()
+
+	go func() {
+		defer close(ch)
+
+		timer := time.NewTimer(0)
+		timer.Stop()
+		defer timer.Stop()
+
+		for offset := range arrivals {
+			timer.Reset(time.Until(start.Add(offset)))
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-timer.C:
+				select {
+				case ch <- now:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// seconds converts a number of seconds into a time.Duration.
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,154 @@
+package rng
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPoissonArrivals(t *testing.T) {
+	t.Run("empty for non-positive rate", func(t *testing.T) {
+		for range PoissonArrivals(0) {
+			t.Fatal("expected no arrivals for rate 0")
+		}
+	})
+
+	t.Run("arrival count matches rate", func(t *testing.T) {
+		count := 0
+		for at := range PoissonArrivals(50) {
+			if at > 100*time.Second {
+				break
+			}
+			count++
+		}
+
+		// 5000 expected arrivals with standard deviation about 71.
+		if math.Abs(float64(count)-5000) > 300 {
+			t.Errorf("expected about 5000 arrivals, got %d", count)
+		}
+	})
+
+	t.Run("arrivals are non-decreasing", func(t *testing.T) {
+		var prev time.Duration
+		n := 0
+		for at := range PoissonArrivals(1000) {
+			if at < prev {
+				t.Fatalf("arrival %v before previous arrival %v", at, prev)
+			}
+			prev = at
+			n++
+			if n == 1000 {
+				break
+			}
+		}
+	})
+}
+
+func TestNonHomogeneousPoissonArrivals(t *testing.T) {
+	t.Run("follows the rate function", func(t *testing.T) {
+		// 10 events per second during the first 50 seconds, then 40 per second.
+		rate := func(t time.Duration) float64 {
+			if t < 50*time.Second {
+				return 10
+			}
+			return 40
+		}
+
+		early, late := 0, 0
+		for at := range NonHomogeneousPoissonArrivals(rate, 40) {
+			if at > 100*time.Second {
+				break
+			}
+			if at < 50*time.Second {
+				early++
+			} else {
+				late++
+			}
+		}
+
+		if math.Abs(float64(early)-500) > 100 {
+			t.Errorf("expected about 500 early arrivals, got %d", early)
+		}
+		if math.Abs(float64(late)-2000) > 200 {
+			t.Errorf("expected about 2000 late arrivals, got %d", late)
+		}
+	})
+
+	t.Run("empty for non-positive max rate", func(t *testing.T) {
+		for range NonHomogeneousPoissonArrivals(func(time.Duration) float64 { return 1 }, 0) {
+			t.Fatal("expected no arrivals for max rate 0")
+		}
+	})
+}
+
+func TestRenewalArrivals(t *testing.T) {
+	t.Run("constant inter-arrival times", func(t *testing.T) {
+		n := 0
+		for at := range RenewalArrivals(Uniform{Min: 2, Max: 2}) {
+			n++
+			if want := time.Duration(n) * 2 * time.Second; at != want {
+				t.Fatalf("arrival %d at %v, want %v", n, at, want)
+			}
+			if n == 10 {
+				break
+			}
+		}
+	})
+
+	t.Run("negative samples are clamped", func(t *testing.T) {
+		n := 0
+		for at := range RenewalArrivals(Uniform{Min: -2, Max: -1}) {
+			if at != 0 {
+				t.Fatalf("expected arrival at 0, got %v", at)
+			}
+			n++
+			if n == 10 {
+				break
+			}
+		}
+	})
+}
+
+func TestArrivalChan(t *testing.T) {
+	t.Run("delivers all arrivals and closes", func(t *testing.T) {
+		offsets := func(yield func(time.Duration) bool) {
+			for i := range 5 {
+				if !yield(time.Duration(i) * time.Millisecond) {
+					return
+				}
+			}
+		}
+
+		start := time.Now()
+		n := 0
+		for at := range ArrivalChan(context.Background(), offsets) {
+			if at.Before(start) {
+				t.Errorf("arrival %v before start %v", at, start)
+			}
+			n++
+		}
+		if n != 5 {
+			t.Errorf("expected 5 arrivals, got %d", n)
+		}
+	})
+
+	t.Run("stops on cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := ArrivalChan(ctx, PoissonArrivals(1000))
+		<-ch
+		cancel()
+
+		timeout := time.After(time.Second)
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+			case <-timeout:
+				t.Fatal("expected channel to close after cancellation")
+			}
+		}
+	})
+}
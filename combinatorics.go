@@ -0,0 +1,232 @@
+package rng
+
+import (
+	"math"
+	"slices"
+)
+
+// maxIntPartition is the largest n accepted by IntPartition, which keeps partition counts
+// well within the range of a float64.
+const maxIntPartition = 50000
+
+// Combination returns k distinct indices randomly selected from [0, n), in increasing order.
+// Every k-combination is equally likely.
+// Returns nil if k <= 0. Panics if k is greater than n.
+func Combination(n, k int) []int {
+	out := PickNIndices(n, k)
+	slices.Sort(out)
+	return out
+}
+
+// Subset returns a random subset of the slice that contains every element independently with
+// probability p, keeping the order of the input.
+func Subset[E any](in []E, p Probability) []E {
+	var out []E
+	for _, v := range in {
+		if p.Check() {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// SubsetN returns a random subset of exactly k elements of the slice, keeping the order of the
+// input. Every subset of size k is equally likely.
+// Returns nil if k <= 0. Panics if k is greater than the length of the slice.
+func SubsetN[E any](in []E, k int) []E {
+	idx := Combination(len(in), k)
+	if idx == nil {
+		return nil
+	}
+
+	out := make([]E, len(idx))
+	for i, j := range idx {
+		out[i] = in[j]
+	}
+	return out
+}
+
+// Mask returns a slice of n booleans with exactly k of them true, at random positions.
+// Every such mask is equally likely. Panics if k < 0 or k > n.
+func Mask(n, k int) []bool {
+	if k < 0 {
+		panic("k must be non-negative")
+	}
+
+	out := make([]bool, n)
+	for _, i := range PickNIndices(n, k) {
+		out[i] = true
+	}
+	return out
+}
+
+// MaskFraction returns a slice of n booleans of which exactly round(p·n) are true, at random
+// positions, so the proportion of trues is as close to p as possible.
+// Panics if p is NaN or outside of [0, 1].
+func MaskFraction(n int, p Probability) []bool {
+	if err := p.Validate(); err != nil {
+		panic(err)
+	}
+	return Mask(n, int(math.Round(float64(p)*float64(n))))
+}
+
+// IntPartition returns a random partition of n: positive integers in non-increasing order that
+// add up to n. Every partition is equally likely.
+//
+// It uses the algorithm of Nijenhuis and Wilf, which needs the number of partitions of every
+// integer up to n. The counts are computed on every call.
+// Returns nil if n <= 0. Panics if n is greater than 50000.
+func IntPartition(n int) []int {
+	if n <= 0 {
+		return nil
+	}
+	if n > maxIntPartition {
+		panic("n must be at most 50000")
+	}
+
+	counts := partitionCounts(n)
+	var out []int
+	for m := n; m > 0; {
+		// Pick the part size d and its multiplicity j with probability d·p(m-jd) / (m·p(m)).
+		target := r.Float64() * float64(m) * counts[m]
+		d, j := 1, 1
+	search:
+		for d = 1; d <= m; d++ {
+			for j = 1; j*d <= m; j++ {
+				target -= float64(d) * counts[m-j*d]
+				if target < 0 {
+					break search
+				}
+			}
+		}
+		if d > m {
+			// Rounding left a tiny remainder; take the last candidate.
+			d, j = m, 1
+		}
+
+		for range j {
+			out = append(out, d)
+		}
+		m -= j * d
+	}
+
+	slices.SortFunc(out, func(a, b int) int { return b - a })
+	return out
+}
+
+// Composition returns a random composition of n: positive integers that add up to n, where the
+// order matters. Every one of the 2^(n-1) compositions is equally likely.
+// Returns nil if n <= 0.
+func Composition(n int) []int {
+	if n <= 0 {
+		return nil
+	}
+
+	var out []int
+	part := 1
+	for range n - 1 {
+		if P50.Check() {
+			out = append(out, part)
+			part = 0
+		}
+		part++
+	}
+	return append(out, part)
+}
+
+// CompositionK returns a random composition of n into exactly k positive parts.
+// Every such composition is equally likely. Panics if k < 1 or k > n.
+func CompositionK(n, k int) []int {
+	if k < 1 || k > n {
+		panic("k must be between 1 and n")
+	}
+
+	out := make([]int, k)
+	prev := 0
+	for i, cut := range append(Combination(n-1, k-1), n-1) {
+		out[i] = cut + 1 - prev
+		prev = cut + 1
+	}
+	return out
+}
+
+// SetPartition returns a random partition of the slice into non-empty blocks. Every one of the
+// Bell-number many partitions is equally likely.
+//
+// It uses Stam's algorithm: the number of urns m is drawn with probability proportional to
+// m^n/m!, every element is thrown into a random urn, and the non-empty urns are the blocks.
+// Blocks are ordered by their first element, and elements keep the order of the input.
+// Returns nil if the slice is empty.
+func SetPartition[E any](in []E) [][]E {
+	if len(in) == 0 {
+		return nil
+	}
+
+	urns := stamUrns(len(in))
+	block := make(map[int]int, urns)
+	var out [][]E
+	for _, v := range in {
+		u := r.IntN(urns)
+		i, ok := block[u]
+		if !ok {
+			i = len(out)
+			block[u] = i
+			out = append(out, nil)
+		}
+		out[i] = append(out[i], v)
+	}
+	return out
+}
+
+// stamUrns draws the number of urns m for Stam's algorithm with probability proportional to
+// m^n/m!. Weights are computed in log space and cut off where they become negligible.
+func stamUrns(n int) int {
+	var logW []float64
+	peak := math.Inf(-1)
+	for m := 1; ; m++ {
+		w := float64(n)*math.Log(float64(m)) - lgamma(float64(m+1))
+		if w < peak-40 {
+			break
+		}
+		peak = max(peak, w)
+		logW = append(logW, w)
+	}
+
+	total := 0.0
+	for i, w := range logW {
+		logW[i] = math.Exp(w - peak)
+		total += logW[i]
+	}
+	target := r.Float64() * total
+	for i, w := range logW {
+		target -= w
+		if target < 0 {
+			return i + 1
+		}
+	}
+	return len(logW)
+}
+
+// partitionCounts returns the number of partitions of every integer from 0 to n, using Euler's
+// pentagonal number recurrence.
+func partitionCounts(n int) []float64 {
+	p := make([]float64, n+1)
+	p[0] = 1
+	for m := 1; m <= n; m++ {
+		for k := 1; ; k++ {
+			g1 := k * (3*k - 1) / 2
+			if g1 > m {
+				break
+			}
+			sign := 1.0
+			if k%2 == 0 {
+				sign = -1
+			}
+			p[m] += sign * p[m-g1]
+			if g2 := g1 + k; g2 <= m {
+				p[m] += sign * p[m-g2]
+			}
+		}
+	}
+	return p
+}
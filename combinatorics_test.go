@@ -0,0 +1,255 @@
+package rng
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// subsetsOf returns every subset of [0, n) as a sorted slice of indices.
+func subsetsOf(n int) [][]int {
+	var out [][]int
+	for mask := range 1 << n {
+		s := []int{}
+		for i := range n {
+			if mask&(1<<i) != 0 {
+				s = append(s, i)
+			}
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func keySet[T any](items []T, keep func(T) bool) map[string]bool {
+	out := make(map[string]bool)
+	for _, v := range items {
+		if keep(v) {
+			out[fmt.Sprint(v)] = true
+		}
+	}
+	return out
+}
+
+// intPartitionsOf returns every partition of n into parts of at most maxPart, in non-increasing order.
+func intPartitionsOf(n, maxPart int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	var out [][]int
+	for part := min(n, maxPart); part >= 1; part-- {
+		for _, rest := range intPartitionsOf(n-part, part) {
+			out = append(out, append([]int{part}, rest...))
+		}
+	}
+	return out
+}
+
+// compositionsOf returns every composition of n.
+func compositionsOf(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	var out [][]int
+	for first := 1; first <= n; first++ {
+		for _, rest := range compositionsOf(n - first) {
+			out = append(out, append([]int{first}, rest...))
+		}
+	}
+	return out
+}
+
+// setPartitionsOf returns every partition of [0, n), with blocks ordered by their first element.
+func setPartitionsOf(n int) [][][]int {
+	if n == 0 {
+		return [][][]int{{}}
+	}
+	var out [][][]int
+	for _, p := range setPartitionsOf(n - 1) {
+		for i := range p {
+			q := make([][]int, len(p))
+			for j := range p {
+				q[j] = slices.Clone(p[j])
+			}
+			q[i] = append(q[i], n-1)
+			out = append(out, q)
+		}
+		out = append(out, append(slices.Clone(p), []int{n - 1}))
+	}
+	return out
+}
+
+func TestCombination(t *testing.T) {
+	t.Run("k <= 0 returns nil", func(t *testing.T) {
+		if got := Combination(5, 0); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("every combination equally likely", func(t *testing.T) {
+		want := keySet(subsetsOf(6), func(s []int) bool { return len(s) == 3 })
+		checkUniform(t, want, func() string { return fmt.Sprint(Combination(6, 3)) })
+	})
+}
+
+func TestSubset(t *testing.T) {
+	t.Run("edge probabilities", func(t *testing.T) {
+		in := []int{1, 2, 3}
+		if got := Subset(in, 0); len(got) != 0 {
+			t.Errorf("expected empty subset, got %v", got)
+		}
+		if got := Subset(in, 1); !slices.Equal(got, in) {
+			t.Errorf("expected the whole slice, got %v", got)
+		}
+	})
+
+	t.Run("every subset equally likely at one half", func(t *testing.T) {
+		want := keySet(subsetsOf(4), func([]int) bool { return true })
+		checkUniform(t, want, func() string {
+			s := Subset(seqSlice(4), P50)
+			if s == nil {
+				s = []int{}
+			}
+			return fmt.Sprint(s)
+		})
+	})
+}
+
+func TestSubsetN(t *testing.T) {
+	t.Run("every subset of size k equally likely", func(t *testing.T) {
+		in := []string{"a", "b", "c", "d", "e"}
+		want := make(map[string]bool)
+		for _, s := range subsetsOf(5) {
+			if len(s) == 2 {
+				want[in[s[0]]+in[s[1]]] = true
+			}
+		}
+		checkUniform(t, want, func() string {
+			s := SubsetN(in, 2)
+			return s[0] + s[1]
+		})
+	})
+}
+
+func TestMask(t *testing.T) {
+	t.Run("panics for invalid k", func(t *testing.T) {
+		for _, k := range []int{-1, 6} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("expected panic for k=%d", k)
+					}
+				}()
+				Mask(5, k)
+			}()
+		}
+	})
+
+	t.Run("every mask equally likely", func(t *testing.T) {
+		want := make(map[string]bool)
+		for _, s := range subsetsOf(5) {
+			if len(s) == 2 {
+				m := make([]bool, 5)
+				for _, i := range s {
+					m[i] = true
+				}
+				want[fmt.Sprint(m)] = true
+			}
+		}
+		checkUniform(t, want, func() string { return fmt.Sprint(Mask(5, 2)) })
+	})
+
+	t.Run("exact fraction", func(t *testing.T) {
+		trues := 0
+		for _, v := range MaskFraction(1000, 0.137) {
+			if v {
+				trues++
+			}
+		}
+		if trues != 137 {
+			t.Errorf("expected 137 trues, got %d", trues)
+		}
+	})
+}
+
+func TestIntPartition(t *testing.T) {
+	t.Run("n <= 0 returns nil", func(t *testing.T) {
+		if got := IntPartition(0); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("partition counts", func(t *testing.T) {
+		counts := partitionCounts(100)
+		for n, want := range map[int]float64{1: 1, 5: 7, 10: 42, 50: 204226, 100: 190569292} {
+			if counts[n] != want {
+				t.Errorf("p(%d) = %f, want %f", n, counts[n], want)
+			}
+		}
+	})
+
+	t.Run("every partition equally likely", func(t *testing.T) {
+		for _, n := range []int{1, 4, 7} {
+			want := keySet(intPartitionsOf(n, n), func([]int) bool { return true })
+			checkUniform(t, want, func() string { return fmt.Sprint(IntPartition(n)) })
+		}
+	})
+
+	t.Run("large n", func(t *testing.T) {
+		p := IntPartition(5000)
+		sum := 0
+		for _, v := range p {
+			sum += v
+		}
+		if sum != 5000 || !slices.IsSortedFunc(p, func(a, b int) int { return b - a }) {
+			t.Errorf("invalid partition of 5000 with sum %d", sum)
+		}
+	})
+}
+
+func TestComposition(t *testing.T) {
+	t.Run("n <= 0 returns nil", func(t *testing.T) {
+		if got := Composition(0); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("every composition equally likely", func(t *testing.T) {
+		want := keySet(compositionsOf(5), func([]int) bool { return true })
+		checkUniform(t, want, func() string { return fmt.Sprint(Composition(5)) })
+	})
+}
+
+func TestCompositionK(t *testing.T) {
+	t.Run("panics for invalid k", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic")
+			}
+		}()
+		CompositionK(3, 4)
+	})
+
+	t.Run("every composition into k parts equally likely", func(t *testing.T) {
+		for _, k := range []int{1, 3, 6} {
+			want := keySet(compositionsOf(6), func(c []int) bool { return len(c) == k })
+			checkUniform(t, want, func() string { return fmt.Sprint(CompositionK(6, k)) })
+		}
+	})
+}
+
+func TestSetPartition(t *testing.T) {
+	t.Run("empty slice returns nil", func(t *testing.T) {
+		if got := SetPartition([]int{}); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("every partition equally likely", func(t *testing.T) {
+		// There are 1, 2, 5, 15 and 52 partitions of 1 to 5 elements.
+		for n := 1; n <= 5; n++ {
+			want := keySet(setPartitionsOf(n), func([][]int) bool { return true })
+			checkUniform(t, want, func() string { return fmt.Sprint(SetPartition(seqSlice(n))) })
+		}
+	})
+}
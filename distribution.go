@@ -0,0 +1,83 @@
+package rng
+
+import (
+	"math"
+)
+
+// Distribution is a univariate probability distribution that can be sampled
+// using the package random source.
+type Distribution interface {
+	// Sample draws a random value from the distribution.
+	Sample() float64
+	// Mean returns the expected value of the distribution.
+	Mean() float64
+	// Variance returns the variance of the distribution.
+	Variance() float64
+	// CDF returns the probability that a sample is less than or equal to x.
+	CDF(x float64) float64
+}
+
+// Uniform is the continuous uniform distribution over [Min, Max).
+type Uniform struct {
+	Min, Max float64
+}
+
+func (u Uniform) Sample() float64   { return u.Min + r.Float64()*(u.Max-u.Min) }
+func (u Uniform) Mean() float64     { return (u.Min + u.Max) / 2 }
+func (u Uniform) Variance() float64 { return (u.Max - u.Min) * (u.Max - u.Min) / 12 }
+
+func (u Uniform) CDF(x float64) float64 {
+	switch {
+	case x <= u.Min:
+		return 0
+	case x >= u.Max:
+		return 1
+	default:
+		return (x - u.Min) / (u.Max - u.Min)
+	}
+}
+
+// Quantile returns the value below which a fraction p of the distribution lies.
+func (u Uniform) Quantile(p float64) float64 { return u.Min + p*(u.Max-u.Min) }
+
+// Normal is the normal (Gaussian) distribution with mean Mu and standard deviation Sigma.
+type Normal struct {
+	Mu, Sigma float64
+}
+
+func (n Normal) Sample() float64       { return n.Mu + n.Sigma*r.NormFloat64() }
+func (n Normal) Mean() float64         { return n.Mu }
+func (n Normal) Variance() float64     { return n.Sigma * n.Sigma }
+func (n Normal) CDF(x float64) float64 { return normCDF((x - n.Mu) / n.Sigma) }
+
+// Quantile returns the value below which a fraction p of the distribution lies.
+func (n Normal) Quantile(p float64) float64 { return n.Mu + n.Sigma*normQuantile(p) }
+
+// Exponential is the exponential distribution with the given Rate (events per unit).
+type Exponential struct {
+	Rate float64
+}
+
+func (e Exponential) Sample() float64   { return r.ExpFloat64() / e.Rate }
+func (e Exponential) Mean() float64     { return 1 / e.Rate }
+func (e Exponential) Variance() float64 { return 1 / (e.Rate * e.Rate) }
+
+func (e Exponential) CDF(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return -math.Expm1(-e.Rate * x)
+}
+
+// Quantile returns the value below which a fraction p of the distribution lies.
+func (e Exponential) Quantile(p float64) float64 { return -math.Log1p(-p) / e.Rate }
+
+// normCDF is the CDF of the standard normal distribution.
+func normCDF(z float64) float64 {
+	return 0.5 * math.Erfc(-z/math.Sqrt2)
+}
+
+// normQuantile is the inverse CDF of the standard normal distribution.
+func normQuantile(p float64) float64 {
+	return math.Sqrt2 * math.Erfinv(2*p-1)
+}
@@ -0,0 +1,49 @@
+package rng
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistributions(t *testing.T) {
+	tests := []struct {
+		name string
+		dist interface {
+			Distribution
+			Quantile(p float64) float64
+		}
+	}{
+		{"uniform", Uniform{Min: 2, Max: 6}},
+		{"normal", Normal{Mu: 3, Sigma: 2}},
+		{"exponential", Exponential{Rate: 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iterations := 50000
+			sum, sumSq := 0.0, 0.0
+			for range iterations {
+				v := tt.dist.Sample()
+				sum += v
+				sumSq += v * v
+			}
+
+			mean := sum / float64(iterations)
+			variance := sumSq/float64(iterations) - mean*mean
+			if math.Abs(mean-tt.dist.Mean()) > 0.05*math.Sqrt(tt.dist.Variance())+0.01 {
+				t.Errorf("expected mean near %f, got %f", tt.dist.Mean(), mean)
+			}
+			// The sample variance of the exponential has a relative standard error of
+			// sqrt(8/n), about 1.3%, so allow 8%.
+			if math.Abs(variance-tt.dist.Variance())/tt.dist.Variance() > 0.08 {
+				t.Errorf("expected variance near %f, got %f", tt.dist.Variance(), variance)
+			}
+
+			for _, p := range []float64{0.1, 0.5, 0.9} {
+				if got := tt.dist.CDF(tt.dist.Quantile(p)); math.Abs(got-p) > 1e-9 {
+					t.Errorf("CDF(Quantile(%f)) = %f", p, got)
+				}
+			}
+		})
+	}
+}
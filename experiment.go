@@ -0,0 +1,217 @@
+package rng
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+)
+
+// Bucket deterministically maps a unit ID, such as a user ID, to a position in [0, 1).
+//
+// The position only depends on the salt and the unit, so the same unit always lands in the
+// same place, while different salts give independent positions. It does not use the package
+// random source.
+func Bucket(salt, unit string) float64 {
+	h := fnv.New64a()
+	h.Write([]byte(salt))
+	h.Write([]byte{0})
+	h.Write([]byte(unit))
+	return float64(mix64(h.Sum64())>>11) / (1 << 53)
+}
+
+// CheckUnit returns true for a deterministic fraction p of all units.
+//
+// Unlike Check, repeated calls with the same salt and unit always return the same result,
+// which makes it suitable for percentage rollouts of feature flags. Increasing p only adds
+// units, so units that were already included stay included.
+func (p Probability) CheckUnit(salt, unit string) bool {
+	return Bucket(salt, unit) < float64(p)
+}
+
+// Exposure describes a unit being assigned to a variant of an experiment.
+type Exposure struct {
+	Experiment string
+	Variant    string
+	Unit       string
+}
+
+type experimentVariant struct {
+	name   string
+	weight float64
+}
+
+// Experiment is a thread-safe A/B experiment that deterministically assigns units to
+// weighted variants.
+//
+// Enrollment and variant assignment use independent hashes of the unit, so ramping the
+// rollout up keeps enrolled units in their variants and only adds new ones. Variants are
+// chosen by weighted rendezvous hashing, so adding or removing a variant, or changing the
+// weight of one variant, only moves units to or from that variant.
+type Experiment struct {
+	mu         sync.Mutex
+	name       string
+	salt       string
+	rollout    Probability
+	variants   []experimentVariant
+	onExposure func(Exposure)
+}
+
+// NewExperiment creates a new experiment with full rollout and no variants.
+// The name is used as the hashing salt unless SetSalt is called.
+func NewExperiment(name string) *Experiment {
+	return &Experiment{name: name, salt: name, rollout: 1}
+}
+
+// Name returns the name of the experiment.
+func (e *Experiment) Name() string {
+	return e.name
+}
+
+// AddVariant adds a variant with the given weight. Variants are selected with probability
+// proportional to their weight, following the same rules as Lottery: weight <= 0 means the
+// variant is never assigned, unless no variant has a positive weight.
+func (e *Experiment) AddVariant(name string, weight float64) *Experiment {
+	e.mu.Lock()
+	e.variants = append(e.variants, experimentVariant{name: name, weight: weight})
+	e.mu.Unlock()
+	return e
+}
+
+// SetSalt replaces the hashing salt. Changing the salt reshuffles all assignments.
+func (e *Experiment) SetSalt(salt string) *Experiment {
+	e.mu.Lock()
+	e.salt = salt
+	e.mu.Unlock()
+	return e
+}
+
+// SetRollout sets the fraction of units enrolled in the experiment.
+func (e *Experiment) SetRollout(p Probability) *Experiment {
+	e.mu.Lock()
+	e.rollout = p
+	e.mu.Unlock()
+	return e
+}
+
+// OnExposure sets a hook that is called every time Assign enrolls a unit.
+func (e *Experiment) OnExposure(fn func(Exposure)) *Experiment {
+	e.mu.Lock()
+	e.onExposure = fn
+	e.mu.Unlock()
+	return e
+}
+
+// Assign returns the variant of the given unit. It returns false if the experiment has
+// no variants or the unit is outside of the rollout. Successful assignments are reported
+// to the exposure hook.
+func (e *Experiment) Assign(unit string) (string, bool) {
+	e.mu.Lock()
+	if len(e.variants) == 0 || !e.rollout.CheckUnit(e.salt, unit) {
+		e.mu.Unlock()
+		return "", false
+	}
+
+	variant := e.pick(unit)
+	hook := e.onExposure
+	e.mu.Unlock()
+
+	if hook != nil {
+		hook(Exposure{Experiment: e.name, Variant: variant, Unit: unit})
+	}
+	return variant, true
+}
+
+// pick returns the variant of a unit by weighted rendezvous hashing: every variant scores the
+// unit with its own hash, scaled by its weight, and the highest score wins. e.mu must be held.
+func (e *Experiment) pick(unit string) string {
+	positive := false
+	for _, v := range e.variants {
+		positive = positive || v.weight > 0
+	}
+
+	best, bestScore := -1, math.Inf(-1)
+	for i, v := range e.variants {
+		weight := v.weight
+		if !positive {
+			weight = 1
+		} else if weight <= 0 {
+			continue
+		}
+		// ln(u)/w is the log of u^(1/w), whose maximum over variants picks each one with
+		// probability proportional to its weight.
+		score := math.Log(Bucket(e.salt+"\x00variant\x00"+v.name, unit)) / weight
+		if best < 0 || score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return e.variants[best].name
+}
+
+type layerSlot struct {
+	experiment *Experiment
+	from, to   float64
+}
+
+// Layer is a thread-safe group of mutually exclusive experiments. Each experiment owns a
+// share of the layer's traffic, so a unit is enrolled in at most one of them.
+type Layer struct {
+	mu    sync.Mutex
+	salt  string
+	slots []layerSlot
+	used  float64
+}
+
+// NewLayer creates an empty layer. The name is used as the hashing salt, so layers with
+// different names split traffic independently of each other.
+func NewLayer(name string) *Layer {
+	return &Layer{salt: name}
+}
+
+// Add gives the experiment the next share of the layer's traffic.
+// Panics if the shares of all experiments in the layer would exceed 1.
+func (l *Layer) Add(share Probability, e *Experiment) *Layer {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if share < 0 || l.used+float64(share) > 1+1e-12 {
+		panic("layer shares must be non-negative and add up to at most 1")
+	}
+	l.slots = append(l.slots, layerSlot{experiment: e, from: l.used, to: l.used + float64(share)})
+	l.used += float64(share)
+	return l
+}
+
+// Assign returns the experiment and variant of the given unit. It returns false if the
+// unit falls outside of every experiment's share or is not enrolled by that experiment.
+func (l *Layer) Assign(unit string) (experiment, variant string, ok bool) {
+	pos := Bucket(l.salt, unit)
+
+	l.mu.Lock()
+	var e *Experiment
+	for _, s := range l.slots {
+		if pos >= s.from && pos < s.to {
+			e = s.experiment
+			break
+		}
+	}
+	l.mu.Unlock()
+
+	if e == nil {
+		return "", "", false
+	}
+	variant, ok = e.Assign(unit)
+	if !ok {
+		return "", "", false
+	}
+	return e.Name(), variant, true
+}
+
+// mix64 is the SplitMix64 finalizer, used to spread hash bits evenly.
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,215 @@
+package rng
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestBucket(t *testing.T) {
+	t.Run("deterministic and in range", func(t *testing.T) {
+		for i := range 1000 {
+			unit := fmt.Sprint("user-", i)
+			b := Bucket("salt", unit)
+			if b < 0 || b >= 1 {
+				t.Fatalf("bucket %f out of range [0, 1)", b)
+			}
+			if Bucket("salt", unit) != b {
+				t.Fatalf("bucket of %s is not deterministic", unit)
+			}
+		}
+	})
+
+	t.Run("salt and unit are separated", func(t *testing.T) {
+		if Bucket("ab", "c") == Bucket("a", "bc") {
+			t.Error("expected different buckets for different salt and unit splits")
+		}
+	})
+
+	t.Run("uniform", func(t *testing.T) {
+		counts := make([]int, 10)
+		for i := range 100000 {
+			counts[int(Bucket("uniform", fmt.Sprint(i))*10)]++
+		}
+		for i, c := range counts {
+			if math.Abs(float64(c)-10000) > 500 {
+				t.Errorf("decile %d has %d units, want about 10000", i, c)
+			}
+		}
+	})
+}
+
+func TestProbability_CheckUnit(t *testing.T) {
+	t.Run("stable per unit", func(t *testing.T) {
+		for i := range 100 {
+			unit := fmt.Sprint(i)
+			want := Probability(0.3).CheckUnit("flag", unit)
+			for range 10 {
+				if Probability(0.3).CheckUnit("flag", unit) != want {
+					t.Fatalf("result for %s changed", unit)
+				}
+			}
+		}
+	})
+
+	t.Run("ramp-up keeps existing units", func(t *testing.T) {
+		included := 0
+		for i := range 10000 {
+			unit := fmt.Sprint(i)
+			if Probability(0.1).CheckUnit("flag", unit) {
+				included++
+				if !Probability(0.5).CheckUnit("flag", unit) {
+					t.Fatalf("unit %s dropped when ramping up", unit)
+				}
+			}
+		}
+		if math.Abs(float64(included)-1000) > 150 {
+			t.Errorf("expected about 1000 units at 10%%, got %d", included)
+		}
+	})
+}
+
+func TestExperiment(t *testing.T) {
+	t.Run("no variants", func(t *testing.T) {
+		if _, ok := NewExperiment("empty").Assign("u"); ok {
+			t.Error("expected no assignment without variants")
+		}
+	})
+
+	t.Run("weighted variants", func(t *testing.T) {
+		e := NewExperiment("checkout").AddVariant("control", 3).AddVariant("treatment", 1).AddVariant("off", 0)
+		counts := make(map[string]int)
+		for i := range 20000 {
+			v, ok := e.Assign(fmt.Sprint(i))
+			if !ok {
+				t.Fatal("expected every unit to be assigned at full rollout")
+			}
+			counts[v]++
+		}
+		if counts["off"] != 0 {
+			t.Errorf("expected no units in zero-weight variant, got %d", counts["off"])
+		}
+		if ratio := float64(counts["treatment"]) / 20000; math.Abs(ratio-0.25) > 0.02 {
+			t.Errorf("expected about 25%% treatment, got %f", ratio)
+		}
+	})
+
+	t.Run("ramp-up keeps variants", func(t *testing.T) {
+		e := NewExperiment("ramp").AddVariant("a", 1).AddVariant("b", 1).SetRollout(0.2)
+		before := make(map[string]string)
+		for i := range 5000 {
+			unit := fmt.Sprint(i)
+			if v, ok := e.Assign(unit); ok {
+				before[unit] = v
+			}
+		}
+
+		e.SetRollout(0.6)
+		after := 0
+		for i := range 5000 {
+			unit := fmt.Sprint(i)
+			v, ok := e.Assign(unit)
+			if ok {
+				after++
+			}
+			if prev, was := before[unit]; was && (!ok || v != prev) {
+				t.Fatalf("unit %s moved from %s to %s", unit, prev, v)
+			}
+		}
+		if after <= len(before) {
+			t.Errorf("expected more units after ramp-up, got %d then %d", len(before), after)
+		}
+	})
+
+	t.Run("changing variants moves few units", func(t *testing.T) {
+		assign := func(e *Experiment) []string {
+			out := make([]string, 10000)
+			for i := range out {
+				out[i], _ = e.Assign(fmt.Sprint(i))
+			}
+			return out
+		}
+		two := assign(NewExperiment("stable").AddVariant("a", 1).AddVariant("b", 1))
+		three := assign(NewExperiment("stable").AddVariant("a", 1).AddVariant("b", 1).AddVariant("c", 1))
+		heavier := assign(NewExperiment("stable").AddVariant("a", 1).AddVariant("b", 2))
+
+		moved := 0
+		for i := range two {
+			if two[i] != three[i] {
+				moved++
+				if three[i] != "c" {
+					t.Fatalf("unit %d moved from %s to %s instead of the new variant", i, two[i], three[i])
+				}
+			}
+			if two[i] != heavier[i] && (two[i] != "a" || heavier[i] != "b") {
+				t.Fatalf("unit %d moved from %s to %s after increasing the weight of b", i, two[i], heavier[i])
+			}
+		}
+		// Adding a third equal variant only needs to move a third of the units.
+		if ratio := float64(moved) / 10000; math.Abs(ratio-1.0/3) > 0.02 {
+			t.Errorf("expected about 33%% of units to move, got %f", ratio)
+		}
+	})
+
+	t.Run("salt reshuffles", func(t *testing.T) {
+		a := NewExperiment("x").AddVariant("a", 1).AddVariant("b", 1)
+		b := NewExperiment("x").AddVariant("a", 1).AddVariant("b", 1).SetSalt("other")
+		differ := 0
+		for i := range 1000 {
+			va, _ := a.Assign(fmt.Sprint(i))
+			vb, _ := b.Assign(fmt.Sprint(i))
+			if va != vb {
+				differ++
+			}
+		}
+		if differ < 400 || differ > 600 {
+			t.Errorf("expected about half of assignments to differ, got %d", differ)
+		}
+	})
+
+	t.Run("exposure hook", func(t *testing.T) {
+		var got []Exposure
+		e := NewExperiment("hook").AddVariant("only", 1).OnExposure(func(x Exposure) { got = append(got, x) })
+		e.Assign("u1")
+		e.SetRollout(0)
+		e.Assign("u2")
+
+		if len(got) != 1 || got[0] != (Exposure{Experiment: "hook", Variant: "only", Unit: "u1"}) {
+			t.Errorf("unexpected exposures %v", got)
+		}
+	})
+}
+
+func TestLayer(t *testing.T) {
+	t.Run("experiments are mutually exclusive", func(t *testing.T) {
+		a := NewExperiment("a").AddVariant("on", 1)
+		b := NewExperiment("b").AddVariant("on", 1)
+		l := NewLayer("layer").Add(0.3, a).Add(0.5, b)
+
+		counts := make(map[string]int)
+		for i := range 20000 {
+			unit := fmt.Sprint(i)
+			exp, _, ok := l.Assign(unit)
+			if !ok {
+				counts[""]++
+				continue
+			}
+			counts[exp]++
+		}
+
+		for name, want := range map[string]float64{"a": 0.3, "b": 0.5, "": 0.2} {
+			if ratio := float64(counts[name]) / 20000; math.Abs(ratio-want) > 0.02 {
+				t.Errorf("experiment %q: expected share %f, got %f", name, want, ratio)
+			}
+		}
+	})
+
+	t.Run("panics when shares exceed 1", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for shares above 1")
+			}
+		}()
+		NewLayer("full").Add(0.7, NewExperiment("a")).Add(0.4, NewExperiment("b"))
+	})
+}
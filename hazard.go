@@ -0,0 +1,66 @@
+package rng
+
+import (
+	"math"
+	"time"
+)
+
+// Rate is a constant hazard rate in events per second, such as "on average every 5 minutes".
+//
+// Events are assumed to follow a Poisson process, so the probability of an event over an
+// interval only depends on the interval's length. This keeps tick-based simulations correct
+// when ticks have different lengths.
+type Rate float64
+
+// Every returns the rate of an event that happens on average once every d. Panics if d <= 0.
+func Every(d time.Duration) Rate {
+	if d <= 0 {
+		panic("d must be greater than 0")
+	}
+	return Rate(1 / d.Seconds())
+}
+
+// Per returns the rate of an event that happens on average n times every d. Panics if d <= 0.
+func Per(n float64, d time.Duration) Rate {
+	return Rate(n) * Every(d)
+}
+
+// Probability returns the probability of at least one event during the elapsed interval,
+// 1 - e^(-rate·elapsed). It returns 0 if the rate or the interval is not positive.
+func (rate Rate) Probability(elapsed time.Duration) Probability {
+	if rate <= 0 || elapsed <= 0 {
+		return 0
+	}
+	return Probability(-math.Expm1(-float64(rate) * elapsed.Seconds()))
+}
+
+// Check returns true if at least one event happens during the elapsed interval.
+func (rate Rate) Check(elapsed time.Duration) bool {
+	return rate.Probability(elapsed).Check()
+}
+
+// Mean returns the average time between events. It returns the maximum duration
+// if the rate is not positive.
+func (rate Rate) Mean() time.Duration {
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return durationOf(1 / float64(rate))
+}
+
+// Next returns a random time until the next event, drawn from the exponential distribution.
+// It returns the maximum duration if the rate is not positive.
+func (rate Rate) Next() time.Duration {
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return durationOf(r.ExpFloat64() / float64(rate))
+}
+
+// durationOf converts seconds to a time.Duration, saturating at the maximum duration.
+func durationOf(s float64) time.Duration {
+	if s >= float64(math.MaxInt64)/float64(time.Second) {
+		return time.Duration(math.MaxInt64)
+	}
+	return seconds(s)
+}
@@ -0,0 +1,103 @@
+package rng
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEvery(t *testing.T) {
+	t.Run("converts interval to rate", func(t *testing.T) {
+		if got := Every(5 * time.Minute); math.Abs(float64(got)-1.0/300) > 1e-15 {
+			t.Errorf("Every(5m) = %v, want %v", got, 1.0/300)
+		}
+		if got := Per(3, time.Hour); math.Abs(float64(got)-3.0/3600) > 1e-15 {
+			t.Errorf("Per(3, 1h) = %v, want %v", got, 3.0/3600)
+		}
+	})
+
+	t.Run("panics for non-positive interval", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for d <= 0")
+			}
+		}()
+		Every(0)
+	})
+}
+
+func TestRate_Probability(t *testing.T) {
+	rate := Every(5 * time.Minute)
+
+	t.Run("exact probability", func(t *testing.T) {
+		got := rate.Probability(5 * time.Minute)
+		if want := 1 - math.Exp(-1); math.Abs(float64(got)-want) > 1e-12 {
+			t.Errorf("Probability(5m) = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("independent of tick length", func(t *testing.T) {
+		// Surviving one 10s tick must equal surviving ten 1s ticks.
+		long := rate.Probability(10 * time.Second).Not()
+		short := rate.Probability(time.Second).Not()
+		if math.Abs(float64(long)-math.Pow(float64(short), 10)) > 1e-12 {
+			t.Errorf("expected %v, got %v", math.Pow(float64(short), 10), long)
+		}
+	})
+
+	t.Run("non-positive inputs", func(t *testing.T) {
+		if got := rate.Probability(0); got != 0 {
+			t.Errorf("expected 0 for zero interval, got %v", got)
+		}
+		if got := Rate(0).Probability(time.Hour); got != 0 {
+			t.Errorf("expected 0 for zero rate, got %v", got)
+		}
+	})
+
+	t.Run("check over variable ticks", func(t *testing.T) {
+		events := 0
+		want := 0.0
+		for i := range 200000 {
+			tick := time.Duration(1+i%7) * time.Second
+			want += float64(Every(time.Minute).Probability(tick))
+			if Every(time.Minute).Check(tick) {
+				events++
+			}
+		}
+
+		if math.Abs(float64(events)-want) > 5*math.Sqrt(want) {
+			t.Errorf("expected about %f events, got %d", want, events)
+		}
+	})
+}
+
+func TestRate_Next(t *testing.T) {
+	t.Run("mean matches rate", func(t *testing.T) {
+		rate := Every(2 * time.Second)
+		if rate.Mean() != 2*time.Second {
+			t.Errorf("Mean() = %v, want 2s", rate.Mean())
+		}
+
+		var total time.Duration
+		iterations := 20000
+		for range iterations {
+			d := rate.Next()
+			if d < 0 {
+				t.Fatalf("negative duration %v", d)
+			}
+			total += d
+		}
+		if mean := total.Seconds() / float64(iterations); math.Abs(mean-2) > 0.1 {
+			t.Errorf("expected mean near 2s, got %fs", mean)
+		}
+	})
+
+	t.Run("zero rate never fires", func(t *testing.T) {
+		if got := Rate(0).Next(); got != time.Duration(math.MaxInt64) {
+			t.Errorf("expected maximum duration, got %v", got)
+		}
+		if got := Rate(0).Mean(); got != time.Duration(math.MaxInt64) {
+			t.Errorf("expected maximum duration, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package rng
+
+import (
+	"maps"
+	"slices"
+)
+
+// PickKey returns a random key of the map. If the map is empty, it returns the zero value of type K.
+//
+// Go randomizes map iteration order, so the result is not reproducible even with a seeded
+// source. Use PickKeyFunc when results must be deterministic.
+func PickKey[M ~map[K]V, K comparable, V any](m M) K {
+	k, _ := PickEntry(m)
+	return k
+}
+
+// PickValue returns the value of a random key of the map. If the map is empty, it returns
+// the zero value of type V. Use PickValueFunc when results must be deterministic.
+func PickValue[M ~map[K]V, K comparable, V any](m M) V {
+	_, v := PickEntry(m)
+	return v
+}
+
+// PickEntry returns a random key of the map and its value. If the map is empty, it returns
+// zero values. Use PickEntryFunc when results must be deterministic.
+func PickEntry[M ~map[K]V, K comparable, V any](m M) (K, V) {
+	if len(m) == 0 {
+		return zeroVal[K](), zeroVal[V]()
+	}
+
+	i := r.IntN(len(m))
+	for k, v := range m {
+		if i == 0 {
+			return k, v
+		}
+		i--
+	}
+	panic("map changed during PickEntry")
+}
+
+// PickNKeys returns n distinct random keys of the map in random order, without copying all keys.
+// Use PickNKeysFunc when results must be deterministic.
+// Returns nil if n <= 0. Panics if n is greater than the length of the map.
+func PickNKeys[M ~map[K]V, K comparable, V any](m M, n int) []K {
+	if n <= 0 {
+		return nil
+	}
+	if n > len(m) {
+		panic("n must be less than the length of the map")
+	}
+
+	slots := make(map[int]int, n)
+	for slot, pos := range PickNIndices(len(m), n) {
+		slots[pos] = slot
+	}
+
+	out := make([]K, n)
+	pos := 0
+	for k := range m {
+		if slot, ok := slots[pos]; ok {
+			out[slot] = k
+		}
+		pos++
+	}
+	return out
+}
+
+// PickKeyWeighted returns a random key of the map, chosen with probability proportional to its
+// value. Weights follow the same rules as Lottery: keys with a value <= 0 are never picked, unless
+// no value is positive. If the map is empty, it returns the zero value of type K.
+// Use PickKeyWeightedFunc when results must be deterministic.
+func PickKeyWeighted[M ~map[K]W, K comparable, W numericType](m M) K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return pickKeyWeighted(m, keys)
+}
+
+// PickKeyFunc is like PickKey, but orders the keys with cmp before picking, so the result
+// only depends on the random source. For ordered key types, pass cmp.Compare.
+func PickKeyFunc[M ~map[K]V, K comparable, V any](m M, cmp func(a, b K) int) K {
+	return Pick(slices.SortedFunc(maps.Keys(m), cmp))
+}
+
+// PickValueFunc is like PickValue, but orders the keys with cmp before picking, so the result
+// only depends on the random source.
+func PickValueFunc[M ~map[K]V, K comparable, V any](m M, cmp func(a, b K) int) V {
+	_, v := PickEntryFunc(m, cmp)
+	return v
+}
+
+// PickEntryFunc is like PickEntry, but orders the keys with cmp before picking, so the result
+// only depends on the random source.
+func PickEntryFunc[M ~map[K]V, K comparable, V any](m M, cmp func(a, b K) int) (K, V) {
+	if len(m) == 0 {
+		return zeroVal[K](), zeroVal[V]()
+	}
+	k := PickKeyFunc(m, cmp)
+	return k, m[k]
+}
+
+// PickNKeysFunc is like PickNKeys, but orders the keys with cmp before picking, so the result
+// only depends on the random source.
+// Returns nil if n <= 0. Panics if n is greater than the length of the map.
+func PickNKeysFunc[M ~map[K]V, K comparable, V any](m M, n int, cmp func(a, b K) int) []K {
+	if n <= 0 {
+		return nil
+	}
+	if n > len(m) {
+		panic("n must be less than the length of the map")
+	}
+	return PickNDistinct(slices.SortedFunc(maps.Keys(m), cmp), n)
+}
+
+// PickKeyWeightedFunc is like PickKeyWeighted, but orders the keys with cmp before picking,
+// so the result only depends on the random source.
+func PickKeyWeightedFunc[M ~map[K]W, K comparable, W numericType](m M, cmp func(a, b K) int) K {
+	return pickKeyWeighted(m, slices.SortedFunc(maps.Keys(m), cmp))
+}
+
+func pickKeyWeighted[M ~map[K]W, K comparable, W numericType](m M, keys []K) K {
+	return PickWeighted(keys, func(k K) float64 { return float64(m[k]) })
+}
@@ -0,0 +1,154 @@
+package rng
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestPickKey(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4}
+
+	t.Run("empty map returns zero values", func(t *testing.T) {
+		var empty map[string]int
+		if got := PickKey(empty); got != "" {
+			t.Errorf("expected empty key, got %q", got)
+		}
+		if got := PickValue(empty); got != 0 {
+			t.Errorf("expected zero value, got %d", got)
+		}
+		if k, v := PickEntryFunc(empty, cmp.Compare[string]); k != "" || v != 0 {
+			t.Errorf("expected zero entry, got %q %d", k, v)
+		}
+	})
+
+	t.Run("uniform keys", func(t *testing.T) {
+		counts := make(map[string]int)
+		for range 20000 {
+			counts[PickKey(m)]++
+		}
+		if chi := chiSquare(counts, len(m)); chi > chiSquareLimit(len(m)-1) {
+			t.Errorf("keys are not uniform (chi-square %f): %v", chi, counts)
+		}
+	})
+
+	t.Run("entries match", func(t *testing.T) {
+		for range 100 {
+			k, v := PickEntry(m)
+			if m[k] != v {
+				t.Fatalf("entry %q=%d does not match map value %d", k, v, m[k])
+			}
+			if v := PickValue(m); v < 1 || v > 4 {
+				t.Fatalf("unexpected value %d", v)
+			}
+		}
+	})
+}
+
+func TestPickNKeys(t *testing.T) {
+	m := map[int]bool{0: true, 1: true, 2: true, 3: true, 4: true}
+
+	t.Run("returns nil for n <= 0", func(t *testing.T) {
+		if got := PickNKeys(m, 0); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("panics when n > map length", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic when n > map length")
+			}
+		}()
+		PickNKeys(m, 6)
+	})
+
+	t.Run("distinct and uniform", func(t *testing.T) {
+		counts := make(map[[2]int]int)
+		for range 20000 {
+			got := PickNKeys(m, 2)
+			if got[0] == got[1] {
+				t.Fatalf("expected distinct keys, got %v", got)
+			}
+			counts[[2]int{got[0], got[1]}]++
+		}
+		if chi := chiSquare(counts, 20); chi > chiSquareLimit(19) {
+			t.Errorf("ordered pairs are not uniform (chi-square %f): %v", chi, counts)
+		}
+	})
+
+	t.Run("all keys", func(t *testing.T) {
+		got := PickNKeys(m, 5)
+		slices.Sort(got)
+		if !slices.Equal(got, []int{0, 1, 2, 3, 4}) {
+			t.Errorf("expected all keys, got %v", got)
+		}
+	})
+}
+
+func TestPickKeyWeighted(t *testing.T) {
+	t.Run("values are weights", func(t *testing.T) {
+		m := map[string]float64{"rare": 1, "common": 9, "never": 0}
+		counts := make(map[string]int)
+		for range 20000 {
+			counts[PickKeyWeighted(m)]++
+		}
+		if counts["never"] != 0 {
+			t.Errorf("expected zero weight never to be picked, got %d", counts["never"])
+		}
+		if ratio := float64(counts["common"]) / 20000; math.Abs(ratio-0.9) > 0.02 {
+			t.Errorf("expected about 90%% common, got %f", ratio)
+		}
+	})
+
+	t.Run("integer weights", func(t *testing.T) {
+		m := map[string]int{"a": 0, "b": 3}
+		for range 100 {
+			if got := PickKeyWeighted(m); got != "b" {
+				t.Fatalf("expected b, got %q", got)
+			}
+		}
+	})
+
+	t.Run("empty map", func(t *testing.T) {
+		if got := PickKeyWeighted(map[string]int{}); got != "" {
+			t.Errorf("expected empty key, got %q", got)
+		}
+	})
+}
+
+func TestPickFunc_Deterministic(t *testing.T) {
+	defer ReplaceRandSource(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+
+	type point struct{ x, y int }
+	byXY := func(a, b point) int {
+		return cmp.Or(cmp.Compare(a.x, b.x), cmp.Compare(a.y, b.y))
+	}
+	points := map[point]int{{0, 0}: 1, {0, 1}: 2, {1, 0}: 3, {1, 1}: 4, {2, 2}: 5}
+	names := map[string]int{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5, "f": 6}
+
+	run := func() []any {
+		ReplaceRandSource(rand.NewPCG(7, 11))
+		var out []any
+		for range 20 {
+			k, v := PickEntryFunc(points, byXY)
+			out = append(out,
+				PickKeyFunc(names, cmp.Compare[string]),
+				PickValueFunc(names, cmp.Compare[string]),
+				k, v,
+				PickKeyWeightedFunc(names, cmp.Compare[string]),
+			)
+			for _, k := range PickNKeysFunc(names, 3, cmp.Compare[string]) {
+				out = append(out, k)
+			}
+		}
+		return out
+	}
+
+	first, second := run(), run()
+	if !slices.Equal(first, second) {
+		t.Errorf("expected identical picks under the same seed:\n%v\n%v", first, second)
+	}
+}
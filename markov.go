@@ -0,0 +1,259 @@
+package rng
+
+import (
+	"encoding/binary"
+	"strings"
+	"sync"
+)
+
+type markovStep[S comparable] struct {
+	State S
+	End   bool
+}
+
+// markovRow holds the outgoing transitions of one history. index maps each step
+// to its position in the lottery so repeated observations add to its weight.
+type markovRow[S comparable] struct {
+	next  *Lottery[markovStep[S]]
+	index map[markovStep[S]]int
+}
+
+func (row *markovRow[S]) add(step markovStep[S], weight float64) {
+	if i, ok := row.index[step]; ok {
+		row.next.mu.Lock()
+		row.next.items[i].Weight += weight
+		row.next.mu.Unlock()
+		return
+	}
+	row.index[step] = row.next.Size()
+	row.next.AppendWeight(weight, step)
+}
+
+// MarkovChain is a thread-safe generic Markov chain whose next state depends on the
+// previous Order() states. Each history has its own Lottery of possible next states,
+// weighted by how often each transition was observed.
+type MarkovChain[S comparable] struct {
+	mu     sync.RWMutex
+	order  int
+	ids    map[S]int
+	rows   map[string]*markovRow[S]
+	starts *Lottery[[]S]
+	index  map[string]int
+	stops  map[S]struct{}
+}
+
+// NewMarkovChain creates an empty Markov chain of the given order. Panics if order < 1.
+func NewMarkovChain[S comparable](order int) *MarkovChain[S] {
+	if order < 1 {
+		panic("order must be greater than 0")
+	}
+	return &MarkovChain[S]{
+		order:  order,
+		ids:    make(map[S]int),
+		rows:   make(map[string]*markovRow[S]),
+		starts: NewLottery[[]S](),
+		index:  make(map[string]int),
+		stops:  make(map[S]struct{}),
+	}
+}
+
+// Order returns the number of previous states that determine the next state.
+func (m *MarkovChain[S]) Order() int {
+	return m.order
+}
+
+// Train records the transitions observed in an example sequence.
+//
+// The first Order() states become a possible starting history for Generate, every following
+// state is recorded as a transition from the states preceding it, and the end of the sequence
+// is recorded as a possible place to stop. Sequences shorter than Order() are ignored.
+func (m *MarkovChain[S]) Train(seq ...S) *MarkovChain[S] {
+	if len(seq) < m.order {
+		return m
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	start := seq[:m.order]
+	key := m.key(start, true)
+	if i, ok := m.index[key]; ok {
+		m.starts.mu.Lock()
+		m.starts.items[i].Weight++
+		m.starts.mu.Unlock()
+	} else {
+		m.index[key] = m.starts.Size()
+		m.starts.Append(append([]S(nil), start...))
+	}
+
+	for i := m.order; i < len(seq); i++ {
+		m.row(seq[i-m.order:i]).add(markovStep[S]{State: seq[i]}, 1)
+	}
+	m.row(seq[len(seq)-m.order:]).add(markovStep[S]{End: true}, 1)
+	return m
+}
+
+// AddTransition adds weight to the transition from the given history to the next state.
+// Panics if the history does not contain exactly Order() states.
+func (m *MarkovChain[S]) AddTransition(weight float64, from []S, to S) *MarkovChain[S] {
+	if len(from) != m.order {
+		panic("history length must equal the order of the chain")
+	}
+
+	m.mu.Lock()
+	m.row(from).add(markovStep[S]{State: to}, weight)
+	m.mu.Unlock()
+	return m
+}
+
+// Stop marks states after which generation ends.
+func (m *MarkovChain[S]) Stop(states ...S) *MarkovChain[S] {
+	m.mu.Lock()
+	for _, s := range states {
+		m.stops[s] = struct{}{}
+	}
+	m.mu.Unlock()
+	return m
+}
+
+// Next draws the state that follows the last Order() states of history.
+//
+// It returns false if history is shorter than Order(), if the history was never observed,
+// or if the drawn transition is the end of a sequence.
+func (m *MarkovChain[S]) Next(history []S) (S, bool) {
+	if len(history) < m.order {
+		return zeroVal[S](), false
+	}
+
+	m.mu.RLock()
+	row, ok := m.rows[m.key(history[len(history)-m.order:], false)]
+	m.mu.RUnlock()
+	if !ok {
+		return zeroVal[S](), false
+	}
+
+	step := row.next.Draw()
+	if step.End {
+		return zeroVal[S](), false
+	}
+	return step.State, true
+}
+
+// Generate draws a starting history from the trained sequences and extends it until a stop
+// state is produced, the chain reaches the end of a sequence or the result has maxLen states.
+// Returns nil if the chain has not been trained or maxLen <= 0.
+func (m *MarkovChain[S]) Generate(maxLen int) []S {
+	start := m.starts.Draw()
+	if start == nil || maxLen <= 0 {
+		return nil
+	}
+	return m.GenerateFrom(start, maxLen)
+}
+
+// GenerateFrom extends a copy of start until a stop state is produced, the chain reaches the
+// end of a sequence or the result has maxLen states. The returned slice includes start,
+// truncated to maxLen states. Returns nil if maxLen <= 0.
+func (m *MarkovChain[S]) GenerateFrom(start []S, maxLen int) []S {
+	if maxLen <= 0 {
+		return nil
+	}
+
+	out := append([]S(nil), start[:min(len(start), maxLen)]...)
+	if len(out) > 0 && m.isStop(out[len(out)-1]) {
+		return out
+	}
+	for len(out) < maxLen {
+		s, ok := m.Next(out)
+		if !ok {
+			break
+		}
+		out = append(out, s)
+		if m.isStop(s) {
+			break
+		}
+	}
+	return out
+}
+
+func (m *MarkovChain[S]) isStop(s S) bool {
+	m.mu.RLock()
+	_, ok := m.stops[s]
+	m.mu.RUnlock()
+	return ok
+}
+
+// row returns the transitions of the given history, creating them if needed.
+// m.mu must be held for writing.
+func (m *MarkovChain[S]) row(history []S) *markovRow[S] {
+	key := m.key(history, true)
+	row, ok := m.rows[key]
+	if !ok {
+		row = &markovRow[S]{next: NewLottery[markovStep[S]](), index: make(map[markovStep[S]]int)}
+		m.rows[key] = row
+	}
+	return row
+}
+
+// key encodes a history as a string of state ids. If assign is false, unknown states
+// map to an id that is never used so the history matches no row.
+func (m *MarkovChain[S]) key(history []S, assign bool) string {
+	buf := make([]byte, 0, len(history)*2)
+	for _, s := range history {
+		id, ok := m.ids[s]
+		if !ok {
+			if !assign {
+				return "\xff"
+			}
+			id = len(m.ids)
+			m.ids[s] = id
+		}
+		buf = binary.AppendUvarint(buf, uint64(id))
+	}
+	return string(buf)
+}
+
+// TextGenerator generates text from a Markov chain trained on words or characters.
+type TextGenerator struct {
+	chain *MarkovChain[string]
+	split func(string) []string
+	sep   string
+}
+
+// NewWordGenerator creates a text generator that works on whitespace-separated words.
+// Panics if order < 1.
+func NewWordGenerator(order int) *TextGenerator {
+	return &TextGenerator{chain: NewMarkovChain[string](order), split: strings.Fields, sep: " "}
+}
+
+// NewCharGenerator creates a text generator that works on individual characters.
+// Panics if order < 1.
+func NewCharGenerator(order int) *TextGenerator {
+	return &TextGenerator{chain: NewMarkovChain[string](order), split: splitRunes, sep: ""}
+}
+
+// Chain returns the underlying Markov chain, for example to mark stop tokens.
+func (g *TextGenerator) Chain() *MarkovChain[string] {
+	return g.chain
+}
+
+// Train trains the generator on one or more example texts. Each text is treated
+// as a separate sequence.
+func (g *TextGenerator) Train(texts ...string) *TextGenerator {
+	for _, text := range texts {
+		g.chain.Train(g.split(text)...)
+	}
+	return g
+}
+
+// Generate returns generated text with at most maxTokens words or characters.
+func (g *TextGenerator) Generate(maxTokens int) string {
+	return strings.Join(g.chain.Generate(maxTokens), g.sep)
+}
+
+func splitRunes(s string) []string {
+	out := make([]string, 0, len(s))
+	for _, c := range s {
+		out = append(out, string(c))
+	}
+	return out
+}
@@ -0,0 +1,157 @@
+package rng
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNewMarkovChain(t *testing.T) {
+	t.Run("panics for order < 1", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for order 0")
+			}
+		}()
+		NewMarkovChain[string](0)
+	})
+
+	t.Run("untrained chain", func(t *testing.T) {
+		m := NewMarkovChain[int](1)
+		if got := m.Generate(10); got != nil {
+			t.Errorf("expected nil from untrained chain, got %v", got)
+		}
+		if _, ok := m.Next([]int{1}); ok {
+			t.Error("expected no next state for untrained chain")
+		}
+	})
+}
+
+func TestMarkovChain_Train(t *testing.T) {
+	t.Run("reproduces deterministic sequence", func(t *testing.T) {
+		m := NewMarkovChain[string](1).Train("home", "search", "product", "checkout")
+		got := m.Generate(100)
+		want := []string{"home", "search", "product", "checkout"}
+		if !slices.Equal(got, want) {
+			t.Errorf("expected %v, got %v", want, got)
+		}
+	})
+
+	t.Run("transition frequencies follow observations", func(t *testing.T) {
+		m := NewMarkovChain[string](1)
+		for range 3 {
+			m.Train("a", "b")
+		}
+		m.Train("a", "c")
+
+		counts := make(map[string]int)
+		for range 10000 {
+			s, ok := m.Next([]string{"a"})
+			if !ok {
+				t.Fatal("expected a next state")
+			}
+			counts[s]++
+		}
+		if ratio := float64(counts["b"]) / 10000; math.Abs(ratio-0.75) > 0.03 {
+			t.Errorf("expected about 75%% b, got %f", ratio)
+		}
+	})
+
+	t.Run("higher order uses longer history", func(t *testing.T) {
+		m := NewMarkovChain[int](2).Train(1, 2, 3).Train(4, 2, 5)
+		for range 100 {
+			if s, ok := m.Next([]int{1, 2}); !ok || s != 3 {
+				t.Fatalf("expected 3 after [1 2], got %d", s)
+			}
+			if s, ok := m.Next([]int{0, 4, 2}); !ok || s != 5 {
+				t.Fatalf("expected 5 after [4 2], got %d", s)
+			}
+		}
+		if _, ok := m.Next([]int{9, 2}); ok {
+			t.Error("expected no next state for unseen history")
+		}
+	})
+
+	t.Run("ignores short sequences", func(t *testing.T) {
+		m := NewMarkovChain[int](3).Train(1, 2)
+		if got := m.Generate(10); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+}
+
+func TestMarkovChain_Generate(t *testing.T) {
+	t.Run("respects maxLen", func(t *testing.T) {
+		m := NewMarkovChain[int](1).AddTransition(1, []int{0}, 0)
+		got := m.GenerateFrom([]int{0}, 5)
+		if len(got) != 5 {
+			t.Errorf("expected 5 states, got %v", got)
+		}
+		if got := m.GenerateFrom([]int{0, 0, 0}, 2); len(got) != 2 {
+			t.Errorf("expected start to be truncated, got %v", got)
+		}
+		if got := m.GenerateFrom([]int{0}, 0); got != nil {
+			t.Errorf("expected nil for maxLen 0, got %v", got)
+		}
+	})
+
+	t.Run("ends at stop states", func(t *testing.T) {
+		m := NewMarkovChain[string](1).
+			AddTransition(1, []string{"browse"}, "browse").
+			AddTransition(1, []string{"browse"}, "buy").
+			AddTransition(1, []string{"buy"}, "browse").
+			Stop("buy")
+
+		for range 100 {
+			got := m.GenerateFrom([]string{"browse"}, 1000)
+			if got[len(got)-1] != "buy" || slices.Index(got, "buy") != len(got)-1 {
+				t.Fatalf("expected journey to end at first buy, got %v", got)
+			}
+		}
+	})
+
+	t.Run("panics for wrong history length", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for wrong history length")
+			}
+		}()
+		NewMarkovChain[int](2).AddTransition(1, []int{1}, 2)
+	})
+}
+
+func TestTextGenerator(t *testing.T) {
+	t.Run("word level", func(t *testing.T) {
+		g := NewWordGenerator(2).Train("the quick brown fox jumps over the lazy dog")
+		if got := g.Generate(100); got != "the quick brown fox jumps over the lazy dog" {
+			t.Errorf("unexpected text %q", got)
+		}
+		if got := g.Generate(3); got != "the quick brown" {
+			t.Errorf("expected text to be limited to 3 words, got %q", got)
+		}
+	})
+
+	t.Run("character level", func(t *testing.T) {
+		g := NewCharGenerator(2).Train("banana", "bandana")
+		for range 100 {
+			got := g.Generate(50)
+			if !strings.HasPrefix(got, "ba") {
+				t.Fatalf("expected text to start with ba, got %q", got)
+			}
+			for _, c := range got {
+				if !strings.ContainsRune("band", c) {
+					t.Fatalf("unexpected character %q in %q", c, got)
+				}
+			}
+		}
+	})
+
+	t.Run("stop tokens", func(t *testing.T) {
+		g := NewWordGenerator(1).Train("one. two. three.")
+		g.Chain().Stop("one.")
+		if got := g.Generate(10); got != "one." {
+			t.Errorf("expected generation to stop at one., got %q", got)
+		}
+	})
+}
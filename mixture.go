@@ -0,0 +1,97 @@
+package rng
+
+import (
+	"iter"
+)
+
+// Mixture is a distribution composed of weighted component distributions.
+//
+// Sampling first selects a component using the same weighting rules as Lottery and then
+// draws a value from it. Components with weight <= 0 are never selected, unless no
+// component has a positive weight, in which case all components are equally likely.
+// A Mixture is itself a Distribution, so mixtures can be nested.
+type Mixture struct {
+	components *Lottery[Distribution]
+}
+
+// NewMixture creates a new Mixture with the provided components, each with a weight of 1.
+func NewMixture(components ...Distribution) *Mixture {
+	return &Mixture{components: NewLottery(components...)}
+}
+
+// Add adds one or more components to the mixture with the specified weight.
+func (m *Mixture) Add(weight float64, components ...Distribution) *Mixture {
+	m.components.AppendWeight(weight, components...)
+	return m
+}
+
+// Size returns the number of components in the mixture.
+func (m *Mixture) Size() int {
+	return m.components.Size()
+}
+
+// Sample selects a component by weight and returns a value drawn from it.
+// If the mixture is empty, it returns 0.
+func (m *Mixture) Sample() float64 {
+	d := m.components.Draw()
+	if d == nil {
+		return 0
+	}
+	return d.Sample()
+}
+
+// Mean returns the weighted mean of the component means.
+func (m *Mixture) Mean() float64 {
+	mean := 0.0
+	for d, w := range m.weights() {
+		mean += w * d.Mean()
+	}
+	return mean
+}
+
+// Variance returns the variance of the mixture, which accounts for both the variance
+// within each component and the spread between component means.
+func (m *Mixture) Variance() float64 {
+	mean, second := 0.0, 0.0
+	for d, w := range m.weights() {
+		mu := d.Mean()
+		mean += w * mu
+		second += w * (d.Variance() + mu*mu)
+	}
+	return second - mean*mean
+}
+
+// CDF returns the weighted sum of the component CDFs at x.
+func (m *Mixture) CDF(x float64) float64 {
+	p := 0.0
+	for d, w := range m.weights() {
+		p += w * d.CDF(x)
+	}
+	return p
+}
+
+// weights yields each component with its normalized selection probability.
+func (m *Mixture) weights() iter.Seq2[Distribution, float64] {
+	items := m.components.Items()
+	total := 0.0
+	for i := range items {
+		if items[i].Weight > 0 {
+			total += items[i].Weight
+		}
+	}
+
+	return func(yield func(Distribution, float64) bool) {
+		for i := range items {
+			w := 1 / float64(len(items))
+			if total > 0 {
+				if items[i].Weight <= 0 {
+					continue
+				}
+				w = items[i].Weight / total
+			}
+			if !yield(items[i].Value, w) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package rng
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMixture(t *testing.T) {
+	t.Run("empty mixture", func(t *testing.T) {
+		m := NewMixture()
+		if m.Size() != 0 {
+			t.Errorf("expected size 0, got %d", m.Size())
+		}
+		if v := m.Sample(); v != 0 {
+			t.Errorf("expected 0 for empty mixture, got %f", v)
+		}
+		if v := m.Mean(); v != 0 {
+			t.Errorf("expected mean 0 for empty mixture, got %f", v)
+		}
+	})
+
+	t.Run("moments of weighted components", func(t *testing.T) {
+		m := NewMixture().
+			Add(0.8, Normal{Mu: 10, Sigma: 1}).
+			Add(0.2, Normal{Mu: 100, Sigma: 5})
+
+		wantMean := 0.8*10 + 0.2*100
+		if math.Abs(m.Mean()-wantMean) > 1e-9 {
+			t.Errorf("expected mean %f, got %f", wantMean, m.Mean())
+		}
+
+		wantVar := 0.8*(1+100) + 0.2*(25+10000) - wantMean*wantMean
+		if math.Abs(m.Variance()-wantVar) > 1e-9 {
+			t.Errorf("expected variance %f, got %f", wantVar, m.Variance())
+		}
+
+		if got := m.CDF(50); math.Abs(got-0.8) > 1e-9 {
+			t.Errorf("expected CDF(50) = 0.8, got %f", got)
+		}
+	})
+
+	t.Run("samples follow component weights", func(t *testing.T) {
+		m := NewMixture().
+			Add(0.8, Uniform{Min: 0, Max: 1}).
+			Add(0.2, Uniform{Min: 10, Max: 11})
+
+		slow := 0
+		iterations := 20000
+		for range iterations {
+			if m.Sample() >= 10 {
+				slow++
+			}
+		}
+
+		ratio := float64(slow) / float64(iterations)
+		if math.Abs(ratio-0.2) > 0.02 {
+			t.Errorf("expected about 20%% slow samples, got %f", ratio)
+		}
+	})
+
+	t.Run("non-positive weights are ignored", func(t *testing.T) {
+		m := NewMixture().
+			Add(1, Uniform{Min: 0, Max: 1}).
+			Add(0, Uniform{Min: 10, Max: 11})
+
+		for range 1000 {
+			if v := m.Sample(); v >= 10 {
+				t.Fatalf("sampled %f from zero-weight component", v)
+			}
+		}
+		if m.Mean() != 0.5 {
+			t.Errorf("expected mean 0.5, got %f", m.Mean())
+		}
+	})
+
+	t.Run("falls back to equal weights", func(t *testing.T) {
+		m := NewMixture().Add(0, Uniform{Min: 0, Max: 1}, Uniform{Min: 1, Max: 2})
+		if m.Mean() != 1 {
+			t.Errorf("expected mean 1, got %f", m.Mean())
+		}
+	})
+
+	t.Run("nested mixture", func(t *testing.T) {
+		inner := NewMixture(Uniform{Min: 0, Max: 2}, Uniform{Min: 2, Max: 4})
+		outer := NewMixture(inner, Uniform{Min: 4, Max: 6})
+		if outer.Mean() != 3.5 {
+			t.Errorf("expected mean 3.5, got %f", outer.Mean())
+		}
+	})
+}
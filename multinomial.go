@@ -0,0 +1,193 @@
+package rng
+
+import (
+	"math"
+)
+
+// Binomial returns the number of successes in n independent trials that each succeed with probability p.
+//
+// It returns 0 if n <= 0 or p <= 0, and n if p >= 1. The expected running time does not depend
+// on n: small means use inversion and larger ones use Hörmann's BTRS transformed rejection method.
+func Binomial(n int, p float64) int {
+	if n <= 0 || !(p > 0) {
+		return 0
+	}
+	if p >= 1 {
+		return n
+	}
+	if p > 0.5 {
+		return n - Binomial(n, 1-p)
+	}
+	if float64(n)*p < 10 {
+		return binomialInversion(n, p)
+	}
+	return binomialBTRS(n, p)
+}
+
+// binomialInversion walks the binomial probabilities from k = 0 until the uniform draw is used up.
+// With n·p < 10 only a few dozen terms carry any mass, so once they underflow to 0 the draw was
+// left over by rounding and is redrawn instead of walking on towards n.
+func binomialInversion(n int, p float64) int {
+	q := 1 - p
+	s := p / q
+	a := float64(n+1) * s
+	f0 := math.Exp(float64(n) * math.Log1p(-p))
+	for {
+		f, u := f0, r.Float64()
+		for k := 0; k < n && f > 0; k++ {
+			if u < f {
+				return k
+			}
+			u -= f
+			f *= a/float64(k+1) - s
+		}
+		if f > 0 {
+			return n
+		}
+	}
+}
+
+func binomialBTRS(n int, p float64) int {
+	nf := float64(n)
+	q := 1 - p
+	spq := math.Sqrt(nf * p * q)
+	b := 1.15 + 2.53*spq
+	a := -0.0873 + 0.0248*b + 0.01*p
+	c := nf*p + 0.5
+	vr := 0.92 - 4.2/b
+	alpha := (2.83 + 5.1/b) * spq
+	m := math.Floor((nf + 1) * p)
+	lpq := math.Log(p / q)
+	lfm := lgamma(m+1) + lgamma(nf-m+1)
+
+	for {
+		u := r.Float64() - 0.5
+		v := r.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + c)
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		if k < 0 || k > nf {
+			continue
+		}
+
+		v = math.Log(v * alpha / (a/(us*us) + b))
+		if v <= lfm-lgamma(k+1)-lgamma(nf-k+1)+(k-m)*lpq {
+			return int(k)
+		}
+	}
+}
+
+// Multinomial distributes n trials across categories and returns the number of trials
+// that landed in each category.
+//
+// Categories are chosen with probability proportional to their weights, following the same rules
+// as Lottery: weights <= 0 never receive trials, unless no weight is positive, in which case all
+// categories are equally likely. The running time grows with the number of categories but not with n.
+// Returns nil if weights is empty.
+func Multinomial(n int, weights []float64) []int {
+	if len(weights) == 0 {
+		return nil
+	}
+
+	total, last := 0.0, -1
+	for i, w := range weights {
+		if w > 0 {
+			total += w
+			last = i
+		}
+	}
+
+	counts := make([]int, len(weights))
+	remaining := n
+	if last < 0 {
+		for i := range weights {
+			counts[i] = Binomial(remaining, 1/float64(len(weights)-i))
+			remaining -= counts[i]
+		}
+		return counts
+	}
+
+	for i, w := range weights {
+		if remaining <= 0 {
+			break
+		}
+		if w <= 0 {
+			continue
+		}
+		if i == last {
+			counts[i] = remaining
+			break
+		}
+		counts[i] = Binomial(remaining, w/total)
+		remaining -= counts[i]
+		total -= w
+	}
+	return counts
+}
+
+// Dirichlet returns a random probability vector drawn from the Dirichlet distribution
+// with the given concentration parameters. The returned values are non-negative and sum to 1.
+//
+// Returns nil if alpha is empty. Panics if any concentration parameter is not positive.
+func Dirichlet(alpha []float64) []float64 {
+	if len(alpha) == 0 {
+		return nil
+	}
+
+	out := make([]float64, len(alpha))
+	sum := 0.0
+	for i, a := range alpha {
+		if !(a > 0) {
+			panic("alpha must be greater than 0")
+		}
+		out[i] = gammaVariate(a)
+		sum += out[i]
+	}
+
+	if sum == 0 {
+		// Every component underflowed, which only happens for tiny concentrations.
+		// In that limit all of the mass goes to a single component.
+		for i, c := range Multinomial(1, alpha) {
+			out[i] = float64(c)
+		}
+		return out
+	}
+
+	for i := range out {
+		out[i] /= sum
+	}
+	return out
+}
+
+// gammaVariate returns a sample from the gamma distribution with the given shape and unit scale
+// using the Marsaglia-Tsang method.
+func gammaVariate(shape float64) float64 {
+	if shape < 1 {
+		return gammaVariate(shape+1) * math.Pow(r.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := r.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := r.Float64()
+		if u < 1-0.0331*x*x*x*x {
+			return d * v
+		}
+		if math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
+
+func lgamma(x float64) float64 {
+	v, _ := math.Lgamma(x)
+	return v
+}
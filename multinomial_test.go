@@ -0,0 +1,185 @@
+package rng
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// cycleSource is a rand.Source that returns its values in a loop.
+type cycleSource struct {
+	values []uint64
+	i      int
+}
+
+func (s *cycleSource) Uint64() uint64 {
+	v := s.values[s.i%len(s.values)]
+	s.i++
+	return v
+}
+
+func TestBinomial(t *testing.T) {
+	t.Run("edge cases", func(t *testing.T) {
+		if v := Binomial(0, 0.5); v != 0 {
+			t.Errorf("expected 0 for n=0, got %d", v)
+		}
+		if v := Binomial(10, 0); v != 0 {
+			t.Errorf("expected 0 for p=0, got %d", v)
+		}
+		if v := Binomial(10, 1); v != 10 {
+			t.Errorf("expected 10 for p=1, got %d", v)
+		}
+		if v := Binomial(10, math.NaN()); v != 0 {
+			t.Errorf("expected 0 for p=NaN, got %d", v)
+		}
+	})
+
+	t.Run("draw left over by rounding", func(t *testing.T) {
+		// For these p, the largest Float64 exceeds the rounded sum of the probabilities
+		// before they underflow, which must not walk on to n.
+		ReplaceRandSource(&cycleSource{values: []uint64{math.MaxUint64, 1 << 62}})
+		defer ReplaceRandSource(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+
+		for _, p := range []float64{6.611856250609004e-13, 5.989180101784277e-12} {
+			if v := Binomial(1<<40, p); v > 100 {
+				t.Errorf("p=%g: expected a small count, got %d", p, v)
+			}
+		}
+	})
+
+	tests := []struct {
+		name string
+		n    int
+		p    float64
+	}{
+		{"inversion", 20, 0.1},
+		{"rejection", 1000, 0.3},
+		{"rejection high p", 500, 0.9},
+		{"huge n", 1 << 40, 0.25},
+		{"huge n tiny p", 1 << 40, 1e-12},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iterations := 20000
+			wantMean := float64(tt.n) * tt.p
+			wantVar := wantMean * (1 - tt.p)
+
+			// Sum deviations from the expected mean, as squaring counts near 2^40 loses
+			// too much precision for the variance.
+			sum, sumSq := 0.0, 0.0
+			for range iterations {
+				v := Binomial(tt.n, tt.p)
+				if v < 0 || v > tt.n {
+					t.Fatalf("result %d out of range [0, %d]", v, tt.n)
+				}
+				d := float64(v) - wantMean
+				sum += d
+				sumSq += d * d
+			}
+
+			mean := wantMean + sum/float64(iterations)
+			variance := sumSq/float64(iterations) - (mean-wantMean)*(mean-wantMean)
+			if math.Abs(mean-wantMean) > 5*math.Sqrt(wantVar/float64(iterations)) {
+				t.Errorf("expected mean near %f, got %f", wantMean, mean)
+			}
+			if math.Abs(variance-wantVar)/wantVar > 0.05 {
+				t.Errorf("expected variance near %f, got %f", wantVar, variance)
+			}
+		})
+	}
+}
+
+func TestMultinomial(t *testing.T) {
+	t.Run("returns nil for empty weights", func(t *testing.T) {
+		if got := Multinomial(10, nil); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("counts sum to n", func(t *testing.T) {
+		for range 100 {
+			counts := Multinomial(1_000_000, []float64{0.5, 0.3, 0.2})
+			sum := 0
+			for _, c := range counts {
+				sum += c
+			}
+			if sum != 1_000_000 {
+				t.Fatalf("expected counts to sum to 1000000, got %d", sum)
+			}
+		}
+	})
+
+	t.Run("proportions follow weights", func(t *testing.T) {
+		weights := []float64{1, 2, 0, 7}
+		counts := Multinomial(1_000_000, weights)
+		if counts[2] != 0 {
+			t.Errorf("expected no trials for zero weight, got %d", counts[2])
+		}
+		for i, w := range weights {
+			want := 1_000_000 * w / 10
+			if math.Abs(float64(counts[i])-want) > 5*math.Sqrt(want+1) {
+				t.Errorf("category %d: expected about %f, got %d", i, want, counts[i])
+			}
+		}
+	})
+
+	t.Run("falls back to equal weights", func(t *testing.T) {
+		counts := Multinomial(30000, []float64{0, -1, 0})
+		for i, c := range counts {
+			if math.Abs(float64(c)-10000) > 500 {
+				t.Errorf("category %d: expected about 10000, got %d", i, c)
+			}
+		}
+	})
+}
+
+func TestDirichlet(t *testing.T) {
+	t.Run("returns nil for empty alpha", func(t *testing.T) {
+		if got := Dirichlet(nil); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("panics for non-positive alpha", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for alpha <= 0")
+			}
+		}()
+		Dirichlet([]float64{1, 0})
+	})
+
+	t.Run("proportions sum to 1 with expected means", func(t *testing.T) {
+		alpha := []float64{0.5, 2, 7.5}
+		iterations := 20000
+		sums := make([]float64, len(alpha))
+		for range iterations {
+			v := Dirichlet(alpha)
+			total := 0.0
+			for i := range v {
+				if v[i] < 0 {
+					t.Fatalf("negative proportion %f", v[i])
+				}
+				total += v[i]
+				sums[i] += v[i]
+			}
+			if math.Abs(total-1) > 1e-9 {
+				t.Fatalf("expected proportions to sum to 1, got %f", total)
+			}
+		}
+
+		for i, a := range alpha {
+			if mean := sums[i] / float64(iterations); math.Abs(mean-a/10) > 0.01 {
+				t.Errorf("component %d: expected mean %f, got %f", i, a/10, mean)
+			}
+		}
+	})
+
+	t.Run("tiny concentrations", func(t *testing.T) {
+		v := Dirichlet([]float64{1e-300, 1e-300})
+		if math.Abs(v[0]+v[1]-1) > 1e-9 {
+			t.Errorf("expected proportions to sum to 1, got %v", v)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package rng
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNotPositiveDefinite is returned when a covariance or correlation matrix
+// is not symmetric positive-definite and cannot be factorized.
+var ErrNotPositiveDefinite = errors.New("matrix is not positive-definite")
+
+// Cholesky returns the lower triangular matrix L such that L·Lᵀ equals the given
+// symmetric positive-definite matrix.
+//
+// Returns an error if the matrix is not square or not symmetric, and ErrNotPositiveDefinite
+// if the factorization breaks down.
+func Cholesky(a [][]float64) ([][]float64, error) {
+	n := len(a)
+	for i := range a {
+		if len(a[i]) != n {
+			return nil, fmt.Errorf("matrix must be square")
+		}
+		for j := range i {
+			if math.Abs(a[i][j]-a[j][i]) > 1e-12*math.Max(1, math.Abs(a[i][j])) {
+				return nil, fmt.Errorf("matrix must be symmetric")
+			}
+		}
+	}
+
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+	for i := range n {
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := range j {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if !(sum > 0) {
+					return nil, ErrNotPositiveDefinite
+				}
+				l[i][i] = math.Sqrt(sum)
+			} else {
+				l[i][j] = sum / l[j][j]
+			}
+		}
+	}
+	return l, nil
+}
+
+// MultivariateNormal is a multivariate normal distribution defined by a mean vector
+// and a covariance matrix.
+type MultivariateNormal struct {
+	mean []float64
+	chol [][]float64
+}
+
+// NewMultivariateNormal creates a multivariate normal distribution with the given mean
+// vector and covariance matrix.
+//
+// Returns an error if the dimensions do not match, and ErrNotPositiveDefinite if the
+// covariance matrix is not positive-definite.
+func NewMultivariateNormal(mean []float64, cov [][]float64) (*MultivariateNormal, error) {
+	if len(mean) != len(cov) {
+		return nil, fmt.Errorf("mean has %d elements but covariance is %dx%d", len(mean), len(cov), len(cov))
+	}
+	chol, err := Cholesky(cov)
+	if err != nil {
+		return nil, err
+	}
+	return &MultivariateNormal{mean: append([]float64(nil), mean...), chol: chol}, nil
+}
+
+// Dim returns the number of dimensions of the distribution.
+func (m *MultivariateNormal) Dim() int {
+	return len(m.mean)
+}
+
+// Sample draws a random vector from the distribution.
+func (m *MultivariateNormal) Sample() []float64 {
+	out := correlatedNormals(m.chol)
+	for i := range out {
+		out[i] += m.mean[i]
+	}
+	return out
+}
+
+// GaussianCopula couples arbitrary marginal distributions through the dependence
+// structure of a multivariate normal distribution with the given correlation matrix.
+type GaussianCopula struct {
+	chol      [][]float64
+	marginals []func(p float64) float64
+}
+
+// NewGaussianCopula creates a Gaussian copula from a correlation matrix and one quantile
+// function per dimension, such as Normal.Quantile or Exponential.Quantile.
+//
+// Returns an error if the number of marginals does not match the matrix, if the diagonal
+// of the matrix is not all ones, and ErrNotPositiveDefinite if the matrix is not positive-definite.
+func NewGaussianCopula(corr [][]float64, marginals ...func(p float64) float64) (*GaussianCopula, error) {
+	if len(marginals) != len(corr) {
+		return nil, fmt.Errorf("got %d marginals for a %dx%d correlation matrix", len(marginals), len(corr), len(corr))
+	}
+	for i := range corr {
+		if i < len(corr[i]) && corr[i][i] != 1 {
+			return nil, fmt.Errorf("correlation matrix must have ones on the diagonal")
+		}
+	}
+	chol, err := Cholesky(corr)
+	if err != nil {
+		return nil, err
+	}
+	return &GaussianCopula{chol: chol, marginals: marginals}, nil
+}
+
+// Dim returns the number of dimensions of the copula.
+func (c *GaussianCopula) Dim() int {
+	return len(c.marginals)
+}
+
+// Sample draws a random vector whose components follow the marginal distributions
+// and are correlated according to the copula.
+func (c *GaussianCopula) Sample() []float64 {
+	out := correlatedNormals(c.chol)
+	for i := range out {
+		out[i] = c.marginals[i](normCDF(out[i]))
+	}
+	return out
+}
+
+// correlatedNormals returns L·z for a vector z of independent standard normal values.
+func correlatedNormals(l [][]float64) []float64 {
+	z := make([]float64, len(l))
+	for i := range z {
+		z[i] = r.NormFloat64()
+	}
+
+	out := make([]float64, len(l))
+	for i := range l {
+		for j := 0; j <= i; j++ {
+			out[i] += l[i][j] * z[j]
+		}
+	}
+	return out
+}
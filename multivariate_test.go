@@ -0,0 +1,140 @@
+package rng
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCholesky(t *testing.T) {
+	t.Run("factorizes positive-definite matrix", func(t *testing.T) {
+		a := [][]float64{{4, 12, -16}, {12, 37, -43}, {-16, -43, 98}}
+		want := [][]float64{{2, 0, 0}, {6, 1, 0}, {-8, 5, 3}}
+
+		l, err := Cholesky(a)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for i := range want {
+			for j := range want[i] {
+				if math.Abs(l[i][j]-want[i][j]) > 1e-12 {
+					t.Errorf("L[%d][%d] = %f, want %f", i, j, l[i][j], want[i][j])
+				}
+			}
+		}
+	})
+
+	t.Run("rejects non-positive-definite matrix", func(t *testing.T) {
+		_, err := Cholesky([][]float64{{1, 2}, {2, 1}})
+		if !errors.Is(err, ErrNotPositiveDefinite) {
+			t.Errorf("expected ErrNotPositiveDefinite, got %v", err)
+		}
+	})
+
+	t.Run("rejects non-square matrix", func(t *testing.T) {
+		if _, err := Cholesky([][]float64{{1, 0}, {0}}); err == nil {
+			t.Error("expected error for non-square matrix")
+		}
+	})
+
+	t.Run("rejects asymmetric matrix", func(t *testing.T) {
+		if _, err := Cholesky([][]float64{{2, 1}, {0, 2}}); err == nil {
+			t.Error("expected error for asymmetric matrix")
+		}
+	})
+}
+
+func TestMultivariateNormal(t *testing.T) {
+	t.Run("returns error for mismatched dimensions", func(t *testing.T) {
+		if _, err := NewMultivariateNormal([]float64{0}, [][]float64{{1, 0}, {0, 1}}); err == nil {
+			t.Error("expected error for mismatched dimensions")
+		}
+	})
+
+	t.Run("sample moments match parameters", func(t *testing.T) {
+		mean := []float64{1, -2}
+		cov := [][]float64{{4, 1.2}, {1.2, 1}}
+		mvn, err := NewMultivariateNormal(mean, cov)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if mvn.Dim() != 2 {
+			t.Errorf("expected dim 2, got %d", mvn.Dim())
+		}
+
+		iterations := 50000
+		var sum [2]float64
+		var sumProd [2][2]float64
+		for range iterations {
+			v := mvn.Sample()
+			for i := range 2 {
+				sum[i] += v[i]
+				for j := range 2 {
+					sumProd[i][j] += v[i] * v[j]
+				}
+			}
+		}
+
+		n := float64(iterations)
+		for i := range 2 {
+			if m := sum[i] / n; math.Abs(m-mean[i]) > 0.05 {
+				t.Errorf("mean[%d] = %f, want %f", i, m, mean[i])
+			}
+			for j := range 2 {
+				c := sumProd[i][j]/n - sum[i]/n*sum[j]/n
+				if math.Abs(c-cov[i][j]) > 0.1 {
+					t.Errorf("cov[%d][%d] = %f, want %f", i, j, c, cov[i][j])
+				}
+			}
+		}
+	})
+}
+
+func TestGaussianCopula(t *testing.T) {
+	t.Run("returns error for wrong number of marginals", func(t *testing.T) {
+		_, err := NewGaussianCopula([][]float64{{1, 0.5}, {0.5, 1}}, Uniform{0, 1}.Quantile)
+		if err == nil {
+			t.Error("expected error for missing marginal")
+		}
+	})
+
+	t.Run("returns error for non-unit diagonal", func(t *testing.T) {
+		_, err := NewGaussianCopula([][]float64{{2, 0}, {0, 1}}, Uniform{0, 1}.Quantile, Uniform{0, 1}.Quantile)
+		if err == nil {
+			t.Error("expected error for non-unit diagonal")
+		}
+	})
+
+	t.Run("couples marginals", func(t *testing.T) {
+		tenure := Exponential{Rate: 0.5}
+		size := Uniform{Min: 10, Max: 20}
+		c, err := NewGaussianCopula([][]float64{{1, 0.8}, {0.8, 1}}, tenure.Quantile, size.Quantile)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		iterations := 20000
+		concordant := 0
+		prev := c.Sample()
+		sum := 0.0
+		for range iterations {
+			v := c.Sample()
+			if v[0] < 0 || v[1] < 10 || v[1] >= 20 {
+				t.Fatalf("sample %v outside of marginal supports", v)
+			}
+			if (v[0]-prev[0])*(v[1]-prev[1]) > 0 {
+				concordant++
+			}
+			sum += v[0]
+			prev = v
+		}
+
+		if mean := sum / float64(iterations); math.Abs(mean-tenure.Mean()) > 0.1 {
+			t.Errorf("expected marginal mean near %f, got %f", tenure.Mean(), mean)
+		}
+		// Kendall's tau for a Gaussian copula is 2/π·asin(ρ) ≈ 0.59, so about 80% of pairs are concordant.
+		if ratio := float64(concordant) / float64(iterations); math.Abs(ratio-0.795) > 0.02 {
+			t.Errorf("expected about 79.5%% concordant pairs, got %f", ratio)
+		}
+	})
+}
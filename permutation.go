@@ -0,0 +1,97 @@
+package rng
+
+import (
+	"iter"
+	"math/bits"
+)
+
+const feistelRounds = 6
+
+// Permutation is a lazy, memory-free random permutation of [0, N).
+//
+// It is a keyed Feistel network over the smallest power-of-four domain that contains N,
+// combined with cycle walking to stay within [0, N). Both directions take a few hash
+// evaluations, so even permutations of huge ranges such as 2^40 use constant memory.
+// It is not cryptographically secure.
+type Permutation struct {
+	n    uint64
+	half uint
+	mask uint64
+	keys [feistelRounds]uint64
+}
+
+// NewPermutation creates a random permutation of [0, n) seeded from the package random source.
+func NewPermutation(n uint64) *Permutation {
+	return NewSeededPermutation(n, r.Uint64())
+}
+
+// NewSeededPermutation creates the permutation of [0, n) determined by seed.
+// The same n and seed always produce the same permutation.
+func NewSeededPermutation(n, seed uint64) *Permutation {
+	width := uint(bits.Len64(max(n, 2) - 1))
+	half := (width + 1) / 2
+
+	p := &Permutation{n: n, half: half, mask: 1<<half - 1}
+	for i := range p.keys {
+		seed += 0x9e3779b97f4a7c15
+		p.keys[i] = mix64(seed)
+	}
+	return p
+}
+
+// Len returns N, the size of the permuted range.
+func (p *Permutation) Len() uint64 {
+	return p.n
+}
+
+// At returns the value at position i of the permutation. Panics if i >= Len().
+func (p *Permutation) At(i uint64) uint64 {
+	if i >= p.n {
+		panic("i must be less than the length of the permutation")
+	}
+	x := p.encrypt(i)
+	for x >= p.n {
+		x = p.encrypt(x)
+	}
+	return x
+}
+
+// Inverse returns the position of x in the permutation, so that At(Inverse(x)) == x.
+// Panics if x >= Len().
+func (p *Permutation) Inverse(x uint64) uint64 {
+	if x >= p.n {
+		panic("x must be less than the length of the permutation")
+	}
+	i := p.decrypt(x)
+	for i >= p.n {
+		i = p.decrypt(i)
+	}
+	return i
+}
+
+// All returns a sequence of every value in [0, N) in permuted order.
+func (p *Permutation) All() iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		for i := uint64(0); i < p.n; i++ {
+			if !yield(p.At(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (p *Permutation) encrypt(x uint64) uint64 {
+	left, right := x>>p.half, x&p.mask
+	for _, k := range p.keys {
+		left, right = right, left^(mix64(right^k)&p.mask)
+	}
+	return left<<p.half | right
+}
+
+func (p *Permutation) decrypt(x uint64) uint64 {
+	left, right := x>>p.half, x&p.mask
+	for i := len(p.keys) - 1; i >= 0; i-- {
+		left, right = right^(mix64(left^p.keys[i])&p.mask), left
+	}
+	return left<<p.half | right
+}
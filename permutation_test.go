@@ -0,0 +1,92 @@
+package rng
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPermutation(t *testing.T) {
+	t.Run("bijection", func(t *testing.T) {
+		for _, n := range []uint64{1, 2, 3, 5, 10, 64, 1000, 12345} {
+			p := NewPermutation(n)
+			if p.Len() != n {
+				t.Fatalf("expected length %d, got %d", n, p.Len())
+			}
+
+			seen := make([]bool, n)
+			count := uint64(0)
+			for x := range p.All() {
+				if x >= n || seen[x] {
+					t.Fatalf("n=%d: value %d out of range or repeated", n, x)
+				}
+				seen[x] = true
+				if i := p.Inverse(x); p.At(i) != x || i != count {
+					t.Fatalf("n=%d: Inverse(%d) = %d, want %d", n, x, i, count)
+				}
+				count++
+			}
+			if count != n {
+				t.Errorf("n=%d: expected %d values, got %d", n, n, count)
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		for range NewPermutation(0).All() {
+			t.Fatal("expected no values for n=0")
+		}
+	})
+
+	t.Run("huge range", func(t *testing.T) {
+		n := uint64(1) << 40
+		p := NewPermutation(n)
+		for i := uint64(0); i < 1000; i++ {
+			x := p.At(i * 1_000_003)
+			if x >= n {
+				t.Fatalf("value %d out of range", x)
+			}
+			if p.Inverse(x) != i*1_000_003 {
+				t.Fatalf("inverse of At(%d) does not round trip", i*1_000_003)
+			}
+		}
+
+		full := NewPermutation(math.MaxUint64)
+		if x := full.At(math.MaxUint64 - 1); full.Inverse(x) != math.MaxUint64-1 {
+			t.Error("expected round trip for full 64-bit range")
+		}
+	})
+
+	t.Run("deterministic under seed", func(t *testing.T) {
+		a, b, c := NewSeededPermutation(1000, 42), NewSeededPermutation(1000, 42), NewSeededPermutation(1000, 43)
+		same, differ := true, false
+		for i := uint64(0); i < 1000; i++ {
+			same = same && a.At(i) == b.At(i)
+			differ = differ || a.At(i) != c.At(i)
+		}
+		if !same {
+			t.Error("expected equal seeds to give equal permutations")
+		}
+		if !differ {
+			t.Error("expected different seeds to give different permutations")
+		}
+	})
+
+	t.Run("positions are uniform across seeds", func(t *testing.T) {
+		counts := make(map[uint64]int)
+		for seed := range uint64(20000) {
+			counts[NewSeededPermutation(10, seed).At(0)]++
+		}
+		if chi := chiSquare(counts, 10); chi > chiSquareLimit(9) {
+			t.Errorf("first value is not uniform (chi-square %f): %v", chi, counts)
+		}
+	})
+
+	t.Run("panics out of range", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for i >= n")
+			}
+		}()
+		NewPermutation(10).At(10)
+	})
+}
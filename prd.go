@@ -0,0 +1,122 @@
+package rng
+
+import (
+	"sync"
+)
+
+// prdCacheSize bounds the number of cached constants. The cache is cleared when it is full, so
+// callers with many distinct probabilities cost recomputation rather than memory.
+const prdCacheSize = 256
+
+var prdConstants = struct {
+	mu sync.Mutex
+	m  map[Probability]float64
+}{m: make(map[Probability]float64)}
+
+// PRD is a thread-safe pseudo-random distribution checker, as used for critical hits in
+// Warcraft III and Dota.
+//
+// Instead of rolling the same probability every time, the chance of success starts at a
+// constant C and grows by C after every failure, resetting on success. C is chosen so that
+// the long-run success rate equals the nominal probability, while long droughts and streaks
+// become much rarer than with Probability.Check.
+type PRD struct {
+	mu       sync.Mutex
+	p        Probability
+	c        float64
+	failures int
+}
+
+// NewPRD creates a pseudo-random distribution checker with the given nominal probability.
+func NewPRD(p Probability) *PRD {
+	return &PRD{p: p, c: PRDConstant(p)}
+}
+
+// Check returns true with the current chance and updates the state: the chance grows after
+// a failure and resets after a success.
+func (d *PRD) Check() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.failures++
+	if Probability(d.c * float64(d.failures)).Check() {
+		d.failures = 0
+		return true
+	}
+	return false
+}
+
+// Reset forgets previous failures, so the next check uses the base chance C.
+func (d *PRD) Reset() {
+	d.mu.Lock()
+	d.failures = 0
+	d.mu.Unlock()
+}
+
+// Probability returns the nominal probability of the checker.
+func (d *PRD) Probability() Probability {
+	return d.p
+}
+
+// C returns the per-step increment of the checker's chance.
+func (d *PRD) C() float64 {
+	return d.c
+}
+
+// PRDConstant returns the per-step increment C of a pseudo-random distribution whose
+// long-run success rate is p.
+//
+// It returns 0 if p <= 0 and 1 if p >= 1. Otherwise C is found by bisection on the exact
+// expected number of trials until success, so it is accurate to floating point precision.
+// Recent results are cached, as the computation gets slower for small p; NewPRD also keeps the
+// constant in the checker.
+func PRDConstant(p Probability) float64 {
+	if !(p > 0) {
+		return 0
+	}
+	if p >= 1 {
+		return 1
+	}
+	prdConstants.mu.Lock()
+	c, ok := prdConstants.m[p]
+	prdConstants.mu.Unlock()
+	if ok {
+		return c
+	}
+
+	lo, hi := 0.0, float64(p)
+	for range 200 {
+		mid := lo + (hi-lo)/2
+		if mid <= lo || mid >= hi {
+			break
+		}
+		if 1/prdExpectedTrials(mid) < float64(p) {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	prdConstants.mu.Lock()
+	if len(prdConstants.m) >= prdCacheSize {
+		clear(prdConstants.m)
+	}
+	prdConstants.m[p] = hi
+	prdConstants.mu.Unlock()
+	return hi
+}
+
+// prdExpectedTrials returns the expected number of trials until success when the chance
+// of success on the n-th trial is min(1, n·c).
+func prdExpectedTrials(c float64) float64 {
+	e, survive := 0.0, 1.0
+	for n := 1; survive > 1e-18; n++ {
+		e += survive
+		q := float64(n) * c
+		if q >= 1 {
+			break
+		}
+		survive *= 1 - q
+	}
+	return e
+}
@@ -0,0 +1,127 @@
+package rng
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPRDConstant(t *testing.T) {
+	tests := []struct {
+		p    Probability
+		want float64
+	}{
+		{0.05, 0.003801658303553139},
+		{0.10, 0.014745844781072675},
+		{0.25, 0.08474409185231699},
+		{0.50, 0.3021030253487422},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.p.String(), func(t *testing.T) {
+			if got := PRDConstant(tt.p); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("PRDConstant(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("bounds", func(t *testing.T) {
+		if got := PRDConstant(0); got != 0 {
+			t.Errorf("expected 0 for p=0, got %v", got)
+		}
+		if got := PRDConstant(-1); got != 0 {
+			t.Errorf("expected 0 for negative p, got %v", got)
+		}
+		if got := PRDConstant(1); got != 1 {
+			t.Errorf("expected 1 for p=1, got %v", got)
+		}
+	})
+
+	t.Run("small probability", func(t *testing.T) {
+		p := Probability(1e-4)
+		c := PRDConstant(p)
+		if rate := 1 / prdExpectedTrials(c); math.Abs(rate-float64(p))/float64(p) > 1e-9 {
+			t.Errorf("expected long-run rate %v, got %v", p, rate)
+		}
+	})
+
+	t.Run("cache stays bounded", func(t *testing.T) {
+		for i := range 3 * prdCacheSize {
+			PRDConstant(Probability(0.3 + float64(i)*1e-6))
+		}
+		prdConstants.mu.Lock()
+		size := len(prdConstants.m)
+		prdConstants.mu.Unlock()
+		if size > prdCacheSize {
+			t.Errorf("expected at most %d cached constants, got %d", prdCacheSize, size)
+		}
+		if c := PRDConstant(0.5); math.Abs(c-0.3021030) > 1e-6 {
+			t.Errorf("expected C=0.3021030 for p=0.5 after eviction, got %v", c)
+		}
+	})
+}
+
+func TestPRD(t *testing.T) {
+	t.Run("long-run rate matches nominal probability", func(t *testing.T) {
+		d := NewPRD(0.25)
+		if d.Probability() != 0.25 || d.C() != PRDConstant(0.25) {
+			t.Errorf("unexpected parameters %v, %v", d.Probability(), d.C())
+		}
+
+		hits := 0
+		iterations := 100000
+		for range iterations {
+			if d.Check() {
+				hits++
+			}
+		}
+		if rate := float64(hits) / float64(iterations); math.Abs(rate-0.25) > 0.01 {
+			t.Errorf("expected rate near 0.25, got %f", rate)
+		}
+	})
+
+	t.Run("droughts are bounded", func(t *testing.T) {
+		d := NewPRD(0.25)
+		maxDrought := int(math.Ceil(1 / d.C()))
+		drought := 0
+		for range 100000 {
+			if d.Check() {
+				drought = 0
+				continue
+			}
+			drought++
+			if drought >= maxDrought {
+				t.Fatalf("drought of %d exceeds bound %d", drought, maxDrought)
+			}
+		}
+	})
+
+	t.Run("reset restores base chance", func(t *testing.T) {
+		d := NewPRD(0.5)
+		for range 3 {
+			d.Check()
+		}
+		d.Reset()
+		hits := 0
+		for range 10000 {
+			d.Reset()
+			if d.Check() {
+				hits++
+			}
+		}
+		if rate := float64(hits) / 10000; math.Abs(rate-d.C()) > 0.02 {
+			t.Errorf("expected first-check rate near %f, got %f", d.C(), rate)
+		}
+	})
+
+	t.Run("certain and impossible", func(t *testing.T) {
+		always, never := NewPRD(1), NewPRD(0)
+		for range 100 {
+			if !always.Check() {
+				t.Fatal("expected p=1 to always succeed")
+			}
+			if never.Check() {
+				t.Fatal("expected p=0 to never succeed")
+			}
+		}
+	})
+}
@@ -1,10 +1,64 @@
 package rng
 
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
 var P50 = Probability(0.5)
 
+// ErrInvalidProbability is returned when a probability is NaN or outside of [0, 1].
+var ErrInvalidProbability = errors.New("invalid probability")
+
 // Probability represents a probability value between 0 and 1 (inclusive).
 type Probability float64
 
+// ParseProbability parses a probability from a string.
+//
+// Accepted formats are decimals ("0.0025"), percentages ("0.25%"), fractions ("1/400"),
+// frequencies ("1 in 400") and odds in favor ("1:399"). Surrounding whitespace is ignored.
+// Returns an error wrapping ErrInvalidProbability if the result is NaN or outside of [0, 1].
+func ParseProbability(s string) (Probability, error) {
+	s = strings.TrimSpace(s)
+
+	var (
+		v   float64
+		err error
+	)
+	if num, ok := strings.CutSuffix(s, "%"); ok {
+		v, err = parseFloat(num)
+		v /= 100
+	} else if num, den, ok := strings.Cut(s, "/"); ok {
+		v, err = parseRatio(num, den, false)
+	} else if num, den, ok := cutFold(s, " in "); ok {
+		v, err = parseRatio(num, den, false)
+	} else if num, den, ok := strings.Cut(s, ":"); ok {
+		v, err = parseRatio(num, den, true)
+	} else {
+		v, err = parseFloat(s)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("parse probability %q: %w", s, err)
+	}
+
+	p := Probability(v)
+	if err := p.Validate(); err != nil {
+		return 0, fmt.Errorf("parse probability %q: %w", s, err)
+	}
+	return p, nil
+}
+
+// Validate returns an error wrapping ErrInvalidProbability if p is NaN or outside of [0, 1].
+func (p Probability) Validate() error {
+	if math.IsNaN(float64(p)) || p < 0 || p > 1 {
+		return fmt.Errorf("%w: %v is not in [0, 1]", ErrInvalidProbability, float64(p))
+	}
+	return nil
+}
+
 // Check returns true with the probability specified by p.
 //
 // If p is less than or equal to 0, it always returns false.
@@ -19,3 +73,205 @@ func (p Probability) Check() bool {
 	}
 	return r.Float64() < float64(p)
 }
+
+// Not returns the probability that the event does not happen, 1 - p.
+func (p Probability) Not() Probability {
+	return 1 - p
+}
+
+// And returns the probability that both p and an independent event q happen.
+func (p Probability) And(q Probability) Probability {
+	return p * q
+}
+
+// Or returns the probability that at least one of p and an independent event q happens.
+func (p Probability) Or(q Probability) Probability {
+	return p + q*(1-p)
+}
+
+// AtLeastOnce returns the probability that the event happens at least once in n independent tries.
+//
+// The result is computed in log space, so it stays accurate for tiny probabilities
+// and large n, where 1 - (1-p)^n would round to 0. Returns 0 if n <= 0.
+func (p Probability) AtLeastOnce(n int) Probability {
+	if n <= 0 {
+		return 0
+	}
+	return Probability(-math.Expm1(float64(n) * math.Log1p(-float64(p))))
+}
+
+// Exactly returns the probability that the event happens exactly k times in n independent tries.
+//
+// The binomial probability is computed in log space to avoid overflow of the binomial
+// coefficient and underflow of the powers. Returns 0 if k < 0 or k > n.
+func (p Probability) Exactly(k, n int) Probability {
+	if k < 0 || k > n {
+		return 0
+	}
+	if p == 0 || p == 1 {
+		if (p == 0 && k == 0) || (p == 1 && k == n) {
+			return 1
+		}
+		return 0
+	}
+
+	kf, nf := float64(k), float64(n)
+	logC := lgamma(nf+1) - lgamma(kf+1) - lgamma(nf-kf+1)
+	return Probability(math.Exp(logC + kf*math.Log(float64(p)) + (nf-kf)*math.Log1p(-float64(p))))
+}
+
+// Odds returns the odds in favor of the event, p / (1 - p).
+func (p Probability) Odds() float64 {
+	return float64(p) / (1 - float64(p))
+}
+
+// LogOdds returns the natural logarithm of the odds in favor of the event, also known as the logit.
+func (p Probability) LogOdds() float64 {
+	return math.Log(float64(p)) - math.Log1p(-float64(p))
+}
+
+// Log returns the natural logarithm of p.
+func (p Probability) Log() float64 {
+	return math.Log(float64(p))
+}
+
+// FromOdds returns the probability corresponding to the given odds in favor.
+func FromOdds(odds float64) Probability {
+	if math.IsInf(odds, 1) {
+		return 1
+	}
+	return Probability(odds / (1 + odds))
+}
+
+// FromLogOdds returns the probability corresponding to the given log-odds, the inverse of LogOdds.
+func FromLogOdds(logOdds float64) Probability {
+	if logOdds >= 0 {
+		return Probability(1 / (1 + math.Exp(-logOdds)))
+	}
+	e := math.Exp(logOdds)
+	return Probability(e / (1 + e))
+}
+
+// FromLog returns the probability whose natural logarithm is logP, the inverse of Log.
+func FromLog(logP float64) Probability {
+	return Probability(math.Exp(logP))
+}
+
+// Update applies Bayes' rule with p as the prior probability of a hypothesis and returns
+// the posterior probability after observing evidence.
+//
+// likelihood is the probability of the evidence if the hypothesis is true, and falsePositive
+// is the probability of the evidence if it is false. The update is done on log-odds, so
+// tiny priors and likelihoods do not underflow. The result is NaN if the evidence is
+// impossible under the prior.
+func (p Probability) Update(likelihood, falsePositive Probability) Probability {
+	return FromLogOdds(p.LogOdds() + likelihood.Log() - falsePositive.Log())
+}
+
+// String returns p as a decimal number, such as "0.0025".
+func (p Probability) String() string {
+	return strconv.FormatFloat(float64(p), 'g', -1, 64)
+}
+
+// Percent returns p as a percentage, such as "0.25%".
+func (p Probability) Percent() string {
+	return formatFloat(float64(p)*100) + "%"
+}
+
+// OneIn returns p as a frequency, such as "1 in 400". A probability of 0 is "0 in 1".
+func (p Probability) OneIn() string {
+	if p == 0 {
+		return "0 in 1"
+	}
+	return "1 in " + formatFloat(1/float64(p))
+}
+
+// OddsFor returns p as odds in favor, such as "1:399". A probability of 0 is "0:1".
+func (p Probability) OddsFor() string {
+	if p == 0 {
+		return "0:1"
+	}
+	return "1:" + formatFloat((1-float64(p))/float64(p))
+}
+
+// MarshalText implements encoding.TextMarshaler. Returns an error if p is invalid.
+func (p Probability) MarshalText() ([]byte, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseProbability.
+func (p *Probability) UnmarshalText(text []byte) error {
+	v, err := ParseProbability(string(text))
+	if err != nil {
+		return err
+	}
+	*p = v
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. Probabilities are encoded as JSON numbers.
+// Returns an error if p is invalid.
+func (p Probability) MarshalJSON() ([]byte, error) {
+	return p.MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts a JSON number, or a JSON string
+// in any format supported by ParseProbability. A JSON null leaves p unchanged.
+func (p *Probability) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("parse probability %s: %w", s, err)
+		}
+		s = unquoted
+	}
+	return p.UnmarshalText([]byte(s))
+}
+
+func parseFloat(s string) (float64, error) {
+	return strconv.ParseFloat(strings.TrimSpace(s), 64)
+}
+
+// parseRatio parses num/den, or num/(num+den) if odds is true. Both parts must be non-negative.
+func parseRatio(num, den string, odds bool) (float64, error) {
+	a, err := parseFloat(num)
+	if err != nil {
+		return 0, err
+	}
+	b, err := parseFloat(den)
+	if err != nil {
+		return 0, err
+	}
+	if a < 0 || b < 0 {
+		return 0, fmt.Errorf("%w: negative parts are not allowed", ErrInvalidProbability)
+	}
+	if odds {
+		b += a
+	}
+	if b == 0 {
+		return 0, fmt.Errorf("division by zero")
+	}
+	return a / b, nil
+}
+
+// cutFold is like strings.Cut but matches sep case-insensitively.
+func cutFold(s, sep string) (before, after string, found bool) {
+	for i := 0; i+len(sep) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(sep)], sep) {
+			return s[:i], s[i+len(sep):], true
+		}
+	}
+	return s, "", false
+}
+
+// formatFloat formats v with enough precision to be readable without floating point noise.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', 15, 64)
+}
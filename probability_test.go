@@ -1,6 +1,9 @@
 package rng
 
 import (
+	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 )
 
@@ -84,3 +87,255 @@ func TestProbability_Check(t *testing.T) {
 		}
 	})
 }
+
+func TestParseProbability(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Probability
+	}{
+		{"0.0025", 0.0025},
+		{"0", 0},
+		{"1", 1},
+		{"0.25%", 0.0025},
+		{" 50 % ", 0.5},
+		{"1/400", 0.0025},
+		{"3 / 4", 0.75},
+		{"1 in 400", 0.0025},
+		{"1 IN 4", 0.25},
+		{"1:399", 0.0025},
+		{"1 : 1", 0.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseProbability(tt.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if math.Abs(float64(got-tt.want)) > 1e-15 {
+				t.Errorf("ParseProbability(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+
+	invalid := []string{"", "abc", "1.7", "-0.1", "NaN", "Inf", "150%", "1/0", "5/4", "1 in 0", "0:0", "x:1", "1/y", "-1/-4", "-1 in -4", "-1:-1", "-1:3"}
+	for _, in := range invalid {
+		t.Run("invalid "+in, func(t *testing.T) {
+			if _, err := ParseProbability(in); err == nil {
+				t.Errorf("expected error for %q", in)
+			}
+		})
+	}
+
+	t.Run("out of range and negative parts wrap ErrInvalidProbability", func(t *testing.T) {
+		for _, in := range []string{"1.7", "-1/-4", "-1 in -4", "-1:-1"} {
+			if _, err := ParseProbability(in); !errors.Is(err, ErrInvalidProbability) {
+				t.Errorf("%q: expected ErrInvalidProbability, got %v", in, err)
+			}
+		}
+	})
+}
+
+func TestProbability_Validate(t *testing.T) {
+	for _, p := range []Probability{0, 0.5, 1} {
+		if err := p.Validate(); err != nil {
+			t.Errorf("unexpected error for %v: %v", p, err)
+		}
+	}
+	for _, p := range []Probability{-0.1, 1.7, Probability(math.NaN()), Probability(math.Inf(1))} {
+		if err := p.Validate(); !errors.Is(err, ErrInvalidProbability) {
+			t.Errorf("expected ErrInvalidProbability for %v, got %v", p, err)
+		}
+	}
+}
+
+func TestProbability_Format(t *testing.T) {
+	tests := []struct {
+		p                            Probability
+		str, percent, oneIn, oddsFor string
+	}{
+		{0.0025, "0.0025", "0.25%", "1 in 400", "1:399"},
+		{0.5, "0.5", "50%", "1 in 2", "1:1"},
+		{0.07, "0.07", "7%", "1 in 14.2857142857143", "1:13.2857142857143"},
+		{1, "1", "100%", "1 in 1", "1:0"},
+		{0, "0", "0%", "0 in 1", "0:1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.str, func(t *testing.T) {
+			if got := tt.p.String(); got != tt.str {
+				t.Errorf("String() = %q, want %q", got, tt.str)
+			}
+			if got := tt.p.Percent(); got != tt.percent {
+				t.Errorf("Percent() = %q, want %q", got, tt.percent)
+			}
+			if got := tt.p.OneIn(); got != tt.oneIn {
+				t.Errorf("OneIn() = %q, want %q", got, tt.oneIn)
+			}
+			if got := tt.p.OddsFor(); got != tt.oddsFor {
+				t.Errorf("OddsFor() = %q, want %q", got, tt.oddsFor)
+			}
+
+			for _, s := range []string{tt.str, tt.percent, tt.oneIn, tt.oddsFor} {
+				back, err := ParseProbability(s)
+				if err != nil {
+					t.Fatalf("failed to parse %q: %v", s, err)
+				}
+				if math.Abs(float64(back-tt.p)) > 1e-12 {
+					t.Errorf("round trip of %q = %v, want %v", s, back, tt.p)
+				}
+			}
+		})
+	}
+}
+
+func TestProbability_Marshal(t *testing.T) {
+	type config struct {
+		Drop Probability `json:"drop"`
+	}
+
+	t.Run("marshals as number", func(t *testing.T) {
+		data, err := json.Marshal(config{Drop: 0.0025})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(data) != `{"drop":0.0025}` {
+			t.Errorf("unexpected JSON %s", data)
+		}
+	})
+
+	t.Run("refuses to marshal invalid probability", func(t *testing.T) {
+		if _, err := json.Marshal(config{Drop: 1.7}); err == nil {
+			t.Error("expected error for invalid probability")
+		}
+		if _, err := Probability(math.NaN()).MarshalText(); err == nil {
+			t.Error("expected error for NaN")
+		}
+	})
+
+	t.Run("unmarshals numbers and strings", func(t *testing.T) {
+		for _, in := range []string{`{"drop":0.0025}`, `{"drop":"0.25%"}`, `{"drop":"1 in 400"}`, `{"drop":"1/400"}`} {
+			var c config
+			if err := json.Unmarshal([]byte(in), &c); err != nil {
+				t.Fatalf("unexpected error for %s: %v", in, err)
+			}
+			if math.Abs(float64(c.Drop)-0.0025) > 1e-15 {
+				t.Errorf("unmarshal %s = %v, want 0.0025", in, c.Drop)
+			}
+		}
+	})
+
+	t.Run("null leaves value unchanged", func(t *testing.T) {
+		c := config{Drop: 0.5}
+		if err := json.Unmarshal([]byte(`{"drop":null}`), &c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if c.Drop != 0.5 {
+			t.Errorf("expected 0.5, got %v", c.Drop)
+		}
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		for _, in := range []string{`{"drop":1.7}`, `{"drop":"NaN"}`, `{"drop":-1}`, `{"drop":true}`} {
+			var c config
+			if err := json.Unmarshal([]byte(in), &c); err == nil {
+				t.Errorf("expected error for %s", in)
+			}
+		}
+	})
+
+	t.Run("text round trip", func(t *testing.T) {
+		var p Probability
+		if err := p.UnmarshalText([]byte("1:3")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		text, err := p.MarshalText()
+		if err != nil || string(text) != "0.25" {
+			t.Errorf("MarshalText() = %q, %v", text, err)
+		}
+	})
+}
+
+func TestProbability_Algebra(t *testing.T) {
+	approx := func(t *testing.T, name string, got, want Probability, tol float64) {
+		t.Helper()
+		if math.Abs(float64(got-want)) > tol*math.Max(1e-300, math.Abs(float64(want))) {
+			t.Errorf("%s = %v, want %v", name, got, want)
+		}
+	}
+
+	t.Run("not, and, or", func(t *testing.T) {
+		p, q := Probability(0.3), Probability(0.6)
+		approx(t, "Not", p.Not(), 0.7, 1e-12)
+		approx(t, "And", p.And(q), 0.18, 1e-12)
+		approx(t, "Or", p.Or(q), 0.72, 1e-12)
+		approx(t, "Or complement", p.Or(q), p.Not().And(q.Not()).Not(), 1e-12)
+	})
+
+	t.Run("at least once", func(t *testing.T) {
+		approx(t, "AtLeastOnce(2)", Probability(0.5).AtLeastOnce(2), 0.75, 1e-12)
+		approx(t, "tiny probability", Probability(1e-12).AtLeastOnce(1000), 1e-9, 1e-6)
+		if got := Probability(0.5).AtLeastOnce(0); got != 0 {
+			t.Errorf("expected 0 for n=0, got %v", got)
+		}
+		if got := Probability(1).AtLeastOnce(3); got != 1 {
+			t.Errorf("expected 1 for certain event, got %v", got)
+		}
+	})
+
+	t.Run("exactly", func(t *testing.T) {
+		approx(t, "Exactly(1, 2)", Probability(0.5).Exactly(1, 2), 0.5, 1e-12)
+		approx(t, "Exactly(2, 5)", Probability(0.3).Exactly(2, 5), 10*0.09*0.343, 1e-12)
+		approx(t, "large n", Probability(0.5).Exactly(5000, 10000), 0.007978646139382, 1e-9)
+		if got := Probability(0.5).Exactly(3, 2); got != 0 {
+			t.Errorf("expected 0 for k > n, got %v", got)
+		}
+		if got := Probability(0).Exactly(0, 4); got != 1 {
+			t.Errorf("expected 1 for zero successes of impossible event, got %v", got)
+		}
+		if got := Probability(1).Exactly(3, 4); got != 0 {
+			t.Errorf("expected 0 for certain event missing once, got %v", got)
+		}
+
+		sum := Probability(0)
+		for k := range 21 {
+			sum += Probability(0.37).Exactly(k, 20)
+		}
+		approx(t, "sum over k", sum, 1, 1e-12)
+	})
+
+	t.Run("odds", func(t *testing.T) {
+		p := Probability(0.2)
+		if got := p.Odds(); math.Abs(got-0.25) > 1e-12 {
+			t.Errorf("Odds() = %v, want 0.25", got)
+		}
+		approx(t, "FromOdds", FromOdds(p.Odds()), p, 1e-12)
+		approx(t, "FromLogOdds", FromLogOdds(p.LogOdds()), p, 1e-12)
+		approx(t, "tiny FromLogOdds", FromLogOdds(Probability(1e-300).LogOdds()), 1e-300, 1e-9)
+		approx(t, "FromLog", FromLog(p.Log()), p, 1e-12)
+		if got := FromOdds(math.Inf(1)); got != 1 {
+			t.Errorf("FromOdds(+Inf) = %v, want 1", got)
+		}
+		if got := FromLogOdds(math.Inf(-1)); got != 0 {
+			t.Errorf("FromLogOdds(-Inf) = %v, want 0", got)
+		}
+	})
+
+	t.Run("bayes update", func(t *testing.T) {
+		// 1% prevalence, 90% sensitivity, 5% false positive rate.
+		want := 0.01 * 0.9 / (0.01*0.9 + 0.99*0.05)
+		approx(t, "Update", Probability(0.01).Update(0.9, 0.05), Probability(want), 1e-12)
+
+		approx(t, "tiny prior", Probability(1e-12).Update(0.99, 1e-6), Probability(1e-12*0.99/(1e-12*0.99+1e-6)), 1e-6)
+
+		if got := Probability(0).Update(0.9, 0.1); got != 0 {
+			t.Errorf("expected impossible prior to stay 0, got %v", got)
+		}
+		if got := Probability(1).Update(0.1, 0.9); got != 1 {
+			t.Errorf("expected certain prior to stay 1, got %v", got)
+		}
+		if got := Probability(0.5).Update(0, 0); !math.IsNaN(float64(got)) {
+			t.Errorf("expected NaN for impossible evidence, got %v", got)
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package rng
+
+import (
+	"iter"
+	"math"
+)
+
+// RandomWalk returns an infinite sequence of positions of a simple random walk on the integers.
+//
+// The sequence starts at start, and each step moves up by one with probability p
+// and down by one otherwise.
+func RandomWalk(start int, p Probability) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		x := start
+		for yield(x) {
+			if p.Check() {
+				x++
+			} else {
+				x--
+			}
+		}
+	}
+}
+
+// LatticeWalk returns an infinite sequence of positions of a simple random walk on the
+// integer lattice with as many dimensions as start has elements.
+//
+// Each step moves by one along a uniformly chosen axis in a uniformly chosen direction.
+// Every yielded position is a fresh slice that the caller may keep.
+// The walk stays at start if it has no dimensions.
+func LatticeWalk(start []int) iter.Seq[[]int] {
+	return func(yield func([]int) bool) {
+		x := append([]int(nil), start...)
+		for yield(append([]int(nil), x...)) {
+			if len(x) == 0 {
+				continue
+			}
+			axis := r.IntN(len(x))
+			if P50.Check() {
+				x[axis]++
+			} else {
+				x[axis]--
+			}
+		}
+	}
+}
+
+// BrownianMotion returns an infinite sequence of values of a Brownian motion sampled every dt.
+//
+// The sequence starts at x0, and each step adds drift·dt plus normally distributed noise
+// with standard deviation sigma·√dt. Panics if dt <= 0.
+func BrownianMotion(x0, drift, sigma, dt float64) iter.Seq[float64] {
+	if dt <= 0 {
+		panic("dt must be greater than 0")
+	}
+
+	sd := sigma * math.Sqrt(dt)
+	return func(yield func(float64) bool) {
+		x := x0
+		for yield(x) {
+			x += drift*dt + sd*r.NormFloat64()
+		}
+	}
+}
+
+// OrnsteinUhlenbeck returns an infinite sequence of values of an Ornstein-Uhlenbeck process
+// sampled every dt.
+//
+// The process starts at x0 and reverts towards mu at rate theta with volatility sigma.
+// Steps use the exact transition distribution, so the result does not depend on how small dt is.
+// A theta of 0 reduces to Brownian motion without drift. Panics if dt <= 0 or theta < 0.
+func OrnsteinUhlenbeck(x0, theta, mu, sigma, dt float64) iter.Seq[float64] {
+	if dt <= 0 {
+		panic("dt must be greater than 0")
+	}
+	if theta < 0 {
+		panic("theta must be greater than or equal to 0")
+	}
+	if theta == 0 {
+		return BrownianMotion(x0, 0, sigma, dt)
+	}
+
+	decay := math.Exp(-theta * dt)
+	sd := sigma * math.Sqrt(-math.Expm1(-2*theta*dt)/(2*theta))
+	return func(yield func(float64) bool) {
+		x := x0
+		for yield(x) {
+			x = mu + (x-mu)*decay + sd*r.NormFloat64()
+		}
+	}
+}
+
+// GeometricBrownianMotion returns an infinite sequence of values of a geometric Brownian motion
+// sampled every dt.
+//
+// The process starts at s0 and grows with drift mu and volatility sigma, as is commonly used
+// to model prices. Steps use the exact log-normal transition. Panics if dt <= 0.
+func GeometricBrownianMotion(s0, mu, sigma, dt float64) iter.Seq[float64] {
+	if dt <= 0 {
+		panic("dt must be greater than 0")
+	}
+
+	step := (mu - sigma*sigma/2) * dt
+	sd := sigma * math.Sqrt(dt)
+	return func(yield func(float64) bool) {
+		s := s0
+		for yield(s) {
+			s *= math.Exp(step + sd*r.NormFloat64())
+		}
+	}
+}
+
+// FillPath fills dst with consecutive values from seq and returns the number of values written,
+// which is less than len(dst) only if seq ends early.
+func FillPath[E any](dst []E, seq iter.Seq[E]) int {
+	if len(dst) == 0 {
+		return 0
+	}
+
+	n := 0
+	for v := range seq {
+		dst[n] = v
+		n++
+		if n == len(dst) {
+			break
+		}
+	}
+	return n
+}
@@ -0,0 +1,158 @@
+package rng
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestRandomWalk(t *testing.T) {
+	t.Run("starts at start and moves by one", func(t *testing.T) {
+		path := make([]int, 1000)
+		FillPath(path, RandomWalk(5, P50))
+		if path[0] != 5 {
+			t.Errorf("expected path to start at 5, got %d", path[0])
+		}
+		for i := 1; i < len(path); i++ {
+			if d := path[i] - path[i-1]; d != 1 && d != -1 {
+				t.Fatalf("step %d moved by %d", i, d)
+			}
+		}
+	})
+
+	t.Run("certain steps", func(t *testing.T) {
+		path := make([]int, 10)
+		FillPath(path, RandomWalk(0, 1))
+		if path[9] != 9 {
+			t.Errorf("expected walk with p=1 to reach 9, got %d", path[9])
+		}
+	})
+}
+
+func TestLatticeWalk(t *testing.T) {
+	t.Run("moves one unit along one axis", func(t *testing.T) {
+		path := make([][]int, 500)
+		FillPath(path, LatticeWalk([]int{0, 0, 0}))
+		for i := 1; i < len(path); i++ {
+			dist := 0
+			for j := range path[i] {
+				dist += max(path[i][j]-path[i-1][j], path[i-1][j]-path[i][j])
+			}
+			if dist != 1 {
+				t.Fatalf("step %d moved by %d", i, dist)
+			}
+		}
+	})
+
+	t.Run("positions are independent copies", func(t *testing.T) {
+		start := []int{1, 1}
+		path := make([][]int, 3)
+		FillPath(path, LatticeWalk(start))
+		if !slices.Equal(path[0], []int{1, 1}) || !slices.Equal(start, []int{1, 1}) {
+			t.Errorf("expected start to be preserved, got %v and %v", path[0], start)
+		}
+		path[1][0] = 100
+		if path[2][0] == 100 {
+			t.Error("expected positions not to share memory")
+		}
+	})
+}
+
+func TestBrownianMotion(t *testing.T) {
+	t.Run("panics for non-positive dt", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for dt <= 0")
+			}
+		}()
+		BrownianMotion(0, 0, 1, 0)
+	})
+
+	t.Run("terminal distribution", func(t *testing.T) {
+		iterations := 5000
+		sum, sumSq := 0.0, 0.0
+		path := make([]float64, 101)
+		for range iterations {
+			FillPath(path, BrownianMotion(1, 0.5, 2, 0.01))
+			sum += path[100]
+			sumSq += path[100] * path[100]
+		}
+
+		mean := sum / float64(iterations)
+		variance := sumSq/float64(iterations) - mean*mean
+		// Allow five standard errors: 2/sqrt(5000) for the mean and 4·sqrt(2/5000) for the variance.
+		if math.Abs(mean-1.5) > 0.15 {
+			t.Errorf("expected mean near 1.5, got %f", mean)
+		}
+		if math.Abs(variance-4) > 0.4 {
+			t.Errorf("expected variance near 4, got %f", variance)
+		}
+	})
+}
+
+func TestOrnsteinUhlenbeck(t *testing.T) {
+	t.Run("reverts to stationary distribution", func(t *testing.T) {
+		path := make([]float64, 50000)
+		FillPath(path, OrnsteinUhlenbeck(100, 2, 3, 1, 0.1))
+
+		sum, sumSq := 0.0, 0.0
+		tail := path[1000:]
+		for _, v := range tail {
+			sum += v
+			sumSq += v * v
+		}
+
+		mean := sum / float64(len(tail))
+		variance := sumSq/float64(len(tail)) - mean*mean
+		if math.Abs(mean-3) > 0.05 {
+			t.Errorf("expected mean near 3, got %f", mean)
+		}
+		if math.Abs(variance-0.25) > 0.03 {
+			t.Errorf("expected variance near 0.25, got %f", variance)
+		}
+	})
+
+	t.Run("zero theta", func(t *testing.T) {
+		path := make([]float64, 3)
+		if n := FillPath(path, OrnsteinUhlenbeck(1, 0, 0, 1, 1)); n != 3 || path[0] != 1 {
+			t.Errorf("expected a path starting at 1, got %v", path)
+		}
+	})
+}
+
+func TestGeometricBrownianMotion(t *testing.T) {
+	t.Run("stays positive with expected growth", func(t *testing.T) {
+		iterations := 5000
+		sum := 0.0
+		path := make([]float64, 253)
+		for range iterations {
+			FillPath(path, GeometricBrownianMotion(100, 0.05, 0.2, 1.0/252))
+			for _, v := range path {
+				if v <= 0 {
+					t.Fatalf("expected positive values, got %f", v)
+				}
+			}
+			sum += path[252]
+		}
+
+		want := 100 * math.Exp(0.05)
+		if mean := sum / float64(iterations); math.Abs(mean-want) > 1.5 {
+			t.Errorf("expected mean near %f, got %f", want, mean)
+		}
+	})
+}
+
+func TestFillPath(t *testing.T) {
+	t.Run("empty destination", func(t *testing.T) {
+		if n := FillPath(nil, RandomWalk(0, P50)); n != 0 {
+			t.Errorf("expected 0, got %d", n)
+		}
+	})
+
+	t.Run("short sequence", func(t *testing.T) {
+		dst := make([]int, 5)
+		if n := FillPath(dst, slices.Values([]int{1, 2})); n != 2 {
+			t.Errorf("expected 2, got %d", n)
+		}
+	})
+}
@@ -0,0 +1,297 @@
+package rng
+
+import (
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// resampleBlock is the number of replicates computed from one random substream.
+const resampleBlock = 256
+
+// BootstrapResult holds the replicates of a bootstrap and the estimates derived from them.
+type BootstrapResult struct {
+	// Estimate is the statistic computed on the original sample.
+	Estimate float64
+	// Bias is the mean of the replicates minus Estimate.
+	Bias float64
+	// StdErr is the sample standard deviation of the replicates, dividing by B-1 for B replicates.
+	StdErr float64
+	// Replicates holds the statistic computed on every resample, sorted in increasing order.
+	Replicates []float64
+
+	jackknife func() []float64
+}
+
+// JackknifeResult holds the leave-one-out replicates of a jackknife and the estimates derived from them.
+type JackknifeResult struct {
+	// Estimate is the statistic computed on the original sample.
+	Estimate float64
+	// Bias is the jackknife estimate of the bias of the statistic.
+	Bias float64
+	// StdErr is the jackknife estimate of the standard error of the statistic.
+	StdErr float64
+	// Replicates holds the statistic computed without element i at index i.
+	Replicates []float64
+}
+
+// PermutationResult holds the p-values of a two-sample permutation test.
+type PermutationResult struct {
+	// Statistic is the statistic computed on the original samples.
+	Statistic float64
+	// Greater is the p-value for the alternative that the statistic is larger than by chance.
+	Greater float64
+	// Less is the p-value for the alternative that the statistic is smaller than by chance.
+	Less float64
+	// TwoSided is the p-value for the alternative that the statistic differs from chance
+	// in either direction, twice the smaller one-sided p-value capped at 1.
+	TwoSided float64
+}
+
+// Bootstrap resamples the slice with replacement and computes the statistic on every resample,
+// seeded from the package random source. See BootstrapSeeded.
+func Bootstrap[E any](in []E, stat func([]E) float64, resamples int) *BootstrapResult {
+	return BootstrapSeeded(in, stat, resamples, r.Uint64())
+}
+
+// BootstrapSeeded resamples the slice with replacement and computes the statistic on every resample.
+//
+// The work is spread over GOMAXPROCS goroutines. Every block of replicates uses its own random
+// substream derived from seed, so the same seed always gives the same replicates however many
+// cores are used. The statistic is called concurrently and must not keep the slice it is given.
+// The result keeps a copy of the sample for BCa, so the slice may be changed afterwards.
+// Panics if the slice is empty or resamples < 1.
+func BootstrapSeeded[E any](in []E, stat func([]E) float64, resamples int, seed uint64) *BootstrapResult {
+	if len(in) == 0 {
+		panic("slice must not be empty")
+	}
+	if resamples < 1 {
+		panic("resamples must be at least 1")
+	}
+
+	reps := replicate(resamples, seed, func() func(*rand.Rand, int) float64 {
+		buf := make([]E, len(in))
+		return func(src *rand.Rand, _ int) float64 {
+			for i := range buf {
+				buf[i] = in[src.IntN(len(in))]
+			}
+			return stat(buf)
+		}
+	})
+	slices.Sort(reps)
+
+	estimate := stat(in)
+	mean, sd := meanStdDev(reps)
+	if resamples > 1 {
+		sd *= math.Sqrt(float64(resamples) / float64(resamples-1))
+	}
+
+	// BCa runs the jackknife later, so keep a copy the caller cannot change in the meantime.
+	data := slices.Clone(in)
+	return &BootstrapResult{
+		Estimate:   estimate,
+		Bias:       mean - estimate,
+		StdErr:     sd,
+		Replicates: reps,
+		jackknife: sync.OnceValue(func() []float64 {
+			if len(data) < 2 {
+				return nil
+			}
+			return Jackknife(data, stat).Replicates
+		}),
+	}
+}
+
+// Percentile returns the percentile confidence interval at the given level, such as 0.95,
+// taken directly from the quantiles of the replicates.
+// Panics if level is not in (0, 1).
+func (b *BootstrapResult) Percentile(level float64) (lo, hi float64) {
+	alpha := checkLevel(level)
+	return quantileSorted(b.Replicates, alpha/2), quantileSorted(b.Replicates, 1-alpha/2)
+}
+
+// BCa returns the bias-corrected and accelerated confidence interval at the given level.
+//
+// It adjusts the percentile interval for the median bias of the replicates and for the
+// skewness of the statistic, estimated by a jackknife on the original sample, which makes
+// it more accurate than Percentile for skewed statistics. The jackknife is computed on the
+// first call. For a sample of one element, which has no jackknife, the acceleration is 0.
+// Panics if level is not in (0, 1).
+func (b *BootstrapResult) BCa(level float64) (lo, hi float64) {
+	alpha := checkLevel(level)
+
+	n := float64(len(b.Replicates))
+	below := float64(countBelow(b.Replicates, b.Estimate))
+	z0 := normQuantile(min(max(below/n, 0.5/n), 1-0.5/n))
+
+	jack := b.jackknife()
+	mean, _ := meanStdDev(jack)
+	num, den := 0.0, 0.0
+	for _, v := range jack {
+		d := mean - v
+		num += d * d * d
+		den += d * d
+	}
+	a := 0.0
+	if den > 0 {
+		a = num / (6 * math.Pow(den, 1.5))
+	}
+
+	adjust := func(p float64) float64 {
+		z := z0 + normQuantile(p)
+		return normCDF(z0 + z/(1-a*z))
+	}
+	return quantileSorted(b.Replicates, adjust(alpha/2)), quantileSorted(b.Replicates, adjust(1-alpha/2))
+}
+
+// Jackknife computes the statistic on every leave-one-out subsample of the slice and derives
+// jackknife estimates of its bias and standard error.
+//
+// The work is spread over GOMAXPROCS goroutines. The statistic is called concurrently and must
+// not keep the slice it is given. Panics if the slice has fewer than 2 elements.
+func Jackknife[E any](in []E, stat func([]E) float64) JackknifeResult {
+	if len(in) < 2 {
+		panic("slice must have at least 2 elements")
+	}
+
+	reps := replicate(len(in), 0, func() func(*rand.Rand, int) float64 {
+		buf := make([]E, len(in)-1)
+		return func(_ *rand.Rand, i int) float64 {
+			copy(buf, in[:i])
+			copy(buf[i:], in[i+1:])
+			return stat(buf)
+		}
+	})
+
+	n := float64(len(in))
+	estimate := stat(in)
+	mean, sd := meanStdDev(reps)
+	return JackknifeResult{
+		Estimate:   estimate,
+		Bias:       (n - 1) * (mean - estimate),
+		StdErr:     sd * math.Sqrt(n-1),
+		Replicates: reps,
+	}
+}
+
+// PermutationTest tests whether two samples come from the same distribution, seeded from the
+// package random source. See PermutationTestSeeded.
+func PermutationTest[E any](a, b []E, stat func(a, b []E) float64, permutations int) PermutationResult {
+	return PermutationTestSeeded(a, b, stat, permutations, r.Uint64())
+}
+
+// PermutationTestSeeded tests whether two samples come from the same distribution.
+//
+// The statistic, such as the difference in means, is computed on the original samples and on
+// random reassignments of the pooled elements to two groups of the original sizes. The p-values
+// are the fraction of reassignments with a statistic at least as extreme as the observed one,
+// counting the observed assignment itself so they are never 0.
+//
+// The work is spread over GOMAXPROCS goroutines with deterministic substreams as in
+// BootstrapSeeded. The statistic is called concurrently and must not keep the slices it is
+// given. Panics if permutations < 1.
+func PermutationTestSeeded[E any](a, b []E, stat func(a, b []E) float64, permutations int, seed uint64) PermutationResult {
+	if permutations < 1 {
+		panic("permutations must be at least 1")
+	}
+
+	pooled := slices.Concat(a, b)
+	reps := replicate(permutations, seed, func() func(*rand.Rand, int) float64 {
+		buf := make([]E, len(pooled))
+		return func(src *rand.Rand, _ int) float64 {
+			copy(buf, pooled)
+			src.Shuffle(len(buf), func(i, j int) { buf[i], buf[j] = buf[j], buf[i] })
+			return stat(buf[:len(a):len(a)], buf[len(a):])
+		}
+	})
+
+	observed := stat(a, b)
+	greater, less := 1, 1
+	for _, v := range reps {
+		if v >= observed {
+			greater++
+		}
+		if v <= observed {
+			less++
+		}
+	}
+
+	total := float64(permutations + 1)
+	res := PermutationResult{
+		Statistic: observed,
+		Greater:   float64(greater) / total,
+		Less:      float64(less) / total,
+	}
+	res.TwoSided = min(1, 2*min(res.Greater, res.Less))
+	return res
+}
+
+// replicate computes n values in parallel. Values are computed in blocks of resampleBlock, each
+// with its own random source derived from seed and the block index, and every goroutine gets its
+// own function from newWorker, so the result does not depend on scheduling.
+func replicate(n int, seed uint64, newWorker func() func(src *rand.Rand, i int) float64) []float64 {
+	out := make([]float64, n)
+	blocks := (n + resampleBlock - 1) / resampleBlock
+
+	var (
+		next atomic.Int64
+		wg   sync.WaitGroup
+	)
+	for range min(runtime.GOMAXPROCS(0), blocks) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn := newWorker()
+			for {
+				block := int(next.Add(1) - 1)
+				if block >= blocks {
+					return
+				}
+				src := rand.New(rand.NewPCG(seed, uint64(block)))
+				for i := block * resampleBlock; i < min(n, (block+1)*resampleBlock); i++ {
+					out[i] = fn(src, i)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return out
+}
+
+func checkLevel(level float64) float64 {
+	if !(level > 0 && level < 1) {
+		panic("level must be between 0 and 1")
+	}
+	return 1 - level
+}
+
+// quantileSorted returns the p-quantile of sorted values, interpolating linearly between them.
+func quantileSorted(sorted []float64, p float64) float64 {
+	pos := p * float64(len(sorted)-1)
+	i := int(pos)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[i] + (pos-float64(i))*(sorted[i+1]-sorted[i])
+}
+
+// countBelow returns the number of sorted values less than v.
+func countBelow(sorted []float64, v float64) int {
+	i, _ := slices.BinarySearch(sorted, v)
+	return i
+}
+
+// meanStdDev returns the mean and the population standard deviation of the values.
+func meanStdDev(values []float64) (mean, sd float64) {
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	for _, v := range values {
+		sd += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sd / float64(len(values)))
+}
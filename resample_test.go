@@ -0,0 +1,233 @@
+package rng
+
+import (
+	"math"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func meanDiff(a, b []float64) float64 {
+	return mean(a) - mean(b)
+}
+
+func sampleOf(d Distribution, n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = d.Sample()
+	}
+	return out
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Run("panics for invalid arguments", func(t *testing.T) {
+		for name, fn := range map[string]func(){
+			"empty":     func() { Bootstrap([]float64{}, mean, 10) },
+			"resamples": func() { Bootstrap([]float64{1}, mean, 0) },
+			"level":     func() { Bootstrap([]float64{1}, mean, 10).Percentile(1) },
+		} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("expected panic for %s", name)
+					}
+				}()
+				fn()
+			}()
+		}
+	})
+
+	t.Run("single element", func(t *testing.T) {
+		res := Bootstrap([]float64{3}, mean, 100)
+		if lo, hi := res.BCa(0.95); lo != 3 || hi != 3 {
+			t.Errorf("expected the interval [3, 3], got [%f, %f]", lo, hi)
+		}
+	})
+
+	t.Run("standard error divides by B-1", func(t *testing.T) {
+		res := BootstrapSeeded([]float64{1, 2, 3, 4, 5}, mean, 50, 3)
+		m, sd := meanStdDev(res.Replicates)
+		want := sd * math.Sqrt(50.0/49)
+		if math.Abs(res.StdErr-want) > 1e-12 || math.Abs(res.Bias-(m-3)) > 1e-12 {
+			t.Errorf("expected standard error %f, got %f", want, res.StdErr)
+		}
+	})
+
+	t.Run("BCa ignores later changes to the input", func(t *testing.T) {
+		data := sampleOf(Exponential{Rate: 1}, 30)
+		res := BootstrapSeeded(data, mean, 2000, 9)
+		fresh := BootstrapSeeded(slices.Clone(data), mean, 2000, 9)
+		for i := range data {
+			data[i] = float64(i * i)
+		}
+
+		lo, hi := res.BCa(0.9)
+		wantLo, wantHi := fresh.BCa(0.9)
+		if lo != wantLo || hi != wantHi {
+			t.Errorf("expected [%f, %f], got [%f, %f]", wantLo, wantHi, lo, hi)
+		}
+	})
+
+	t.Run("deterministic across core counts", func(t *testing.T) {
+		data := sampleOf(Normal{Mu: 0, Sigma: 1}, 50)
+
+		prev := runtime.GOMAXPROCS(1)
+		serial := BootstrapSeeded(data, mean, 3000, 42)
+		runtime.GOMAXPROCS(max(prev, 4))
+		parallel := BootstrapSeeded(data, mean, 3000, 42)
+		runtime.GOMAXPROCS(prev)
+
+		if !slices.Equal(serial.Replicates, parallel.Replicates) {
+			t.Error("expected the same replicates for the same seed")
+		}
+		if other := BootstrapSeeded(data, mean, 3000, 43); slices.Equal(other.Replicates, serial.Replicates) {
+			t.Error("expected different replicates for a different seed")
+		}
+	})
+
+	t.Run("standard error of the mean", func(t *testing.T) {
+		data := sampleOf(Normal{Mu: 10, Sigma: 2}, 400)
+		res := Bootstrap(data, mean, 4000)
+
+		_, sd := meanStdDev(data)
+		want := sd / math.Sqrt(float64(len(data)))
+		if math.Abs(res.StdErr-want) > 0.1*want {
+			t.Errorf("expected standard error near %f, got %f", want, res.StdErr)
+		}
+		if math.Abs(res.Bias) > 0.1*want {
+			t.Errorf("expected negligible bias, got %f", res.Bias)
+		}
+		if res.Estimate != mean(data) {
+			t.Errorf("expected estimate %f, got %f", mean(data), res.Estimate)
+		}
+	})
+
+	t.Run("interval coverage", func(t *testing.T) {
+		const datasets = 400
+		exp := Exponential{Rate: 1}
+		percentile, bca := 0, 0
+		for range datasets {
+			res := Bootstrap(sampleOf(exp, 40), mean, 1000)
+			if lo, hi := res.Percentile(0.9); lo <= 1 && 1 <= hi {
+				percentile++
+			}
+			if lo, hi := res.BCa(0.9); lo <= 1 && 1 <= hi {
+				bca++
+			}
+		}
+
+		// Coverage should be about 90%, a little less for the percentile interval on skewed data;
+		// the binomial standard deviation is about 1.5%.
+		for name, covered := range map[string]int{"percentile": percentile, "BCa": bca} {
+			if rate := float64(covered) / datasets; rate < 0.8 || rate > 0.97 {
+				t.Errorf("%s: expected about 90%% coverage, got %.1f%%", name, rate*100)
+			}
+		}
+	})
+
+	t.Run("BCa shifts right for skewed data", func(t *testing.T) {
+		data := sampleOf(Exponential{Rate: 1}, 30)
+		res := BootstrapSeeded(data, mean, 5000, 7)
+		_, pHi := res.Percentile(0.95)
+		_, bHi := res.BCa(0.95)
+		if bHi <= pHi {
+			t.Errorf("expected the BCa upper bound above %f, got %f", pHi, bHi)
+		}
+	})
+}
+
+func TestJackknife(t *testing.T) {
+	t.Run("panics for a single element", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic")
+			}
+		}()
+		Jackknife([]float64{1}, mean)
+	})
+
+	t.Run("mean", func(t *testing.T) {
+		data := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+		res := Jackknife(data, mean)
+
+		// For the mean, the jackknife standard error equals the usual s/sqrt(n).
+		_, sd := meanStdDev(data)
+		want := sd * math.Sqrt(8.0/7) / math.Sqrt(8)
+		if math.Abs(res.StdErr-want) > 1e-12 || math.Abs(res.Bias) > 1e-12 {
+			t.Errorf("expected standard error %f and no bias, got %f and %f", want, res.StdErr, res.Bias)
+		}
+		if res.Replicates[0] != (mean(data)*8-2)/7 {
+			t.Errorf("unexpected first replicate %f", res.Replicates[0])
+		}
+	})
+
+	t.Run("corrects the bias of the plug-in variance", func(t *testing.T) {
+		data := []float64{2, 4, 4, 4, 5, 5, 7, 9}
+		variance := func(v []float64) float64 {
+			_, sd := meanStdDev(v)
+			return sd * sd
+		}
+		res := Jackknife(data, variance)
+
+		// The bias-corrected plug-in variance is the unbiased sample variance.
+		if got, want := res.Estimate-res.Bias, variance(data)*8/7; math.Abs(got-want) > 1e-12 {
+			t.Errorf("expected corrected variance %f, got %f", want, got)
+		}
+	})
+}
+
+func TestPermutationTest(t *testing.T) {
+	t.Run("matches the exact distribution", func(t *testing.T) {
+		// Of the 20 ways to split six values into two groups of three,
+		// only the observed one has a difference in means as low as -3.
+		a, b := []float64{1, 2, 3}, []float64{4, 5, 6}
+		res := PermutationTestSeeded(a, b, meanDiff, 40000, 1)
+
+		if res.Statistic != -3 {
+			t.Errorf("expected statistic -3, got %f", res.Statistic)
+		}
+		if math.Abs(res.Less-0.05) > 0.005 || math.Abs(res.TwoSided-0.1) > 0.01 {
+			t.Errorf("expected p-values 0.05 and 0.1, got %f and %f", res.Less, res.TwoSided)
+		}
+		if res.Greater < 0.99 {
+			t.Errorf("expected greater p-value near 1, got %f", res.Greater)
+		}
+	})
+
+	t.Run("same distribution", func(t *testing.T) {
+		small := 0
+		for range 100 {
+			a, b := sampleOf(Normal{Mu: 0, Sigma: 1}, 20), sampleOf(Normal{Mu: 0, Sigma: 1}, 30)
+			if PermutationTest(a, b, meanDiff, 500).TwoSided < 0.05 {
+				small++
+			}
+		}
+		if small > 15 {
+			t.Errorf("expected about 5 significant results, got %d", small)
+		}
+	})
+
+	t.Run("shifted distribution", func(t *testing.T) {
+		// A shift of two standard deviations is ten standard errors of the difference in means.
+		a, b := sampleOf(Normal{Mu: 2, Sigma: 1}, 50), sampleOf(Normal{Mu: 0, Sigma: 1}, 50)
+		res := PermutationTest(a, b, meanDiff, 2000)
+		if res.Greater > 0.01 {
+			t.Errorf("expected a significant result, got p=%f", res.Greater)
+		}
+	})
+
+	t.Run("deterministic under a seed", func(t *testing.T) {
+		a, b := []float64{1, 5, 2, 8}, []float64{3, 9, 4}
+		if PermutationTestSeeded(a, b, meanDiff, 1000, 5) != PermutationTestSeeded(a, b, meanDiff, 1000, 5) {
+			t.Error("expected the same result for the same seed")
+		}
+	})
+}
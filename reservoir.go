@@ -0,0 +1,209 @@
+package rng
+
+import (
+	"bufio"
+	"cmp"
+	"container/heap"
+	"iter"
+	"math"
+	"slices"
+)
+
+// ReservoirR returns up to k items chosen uniformly at random from seq in a single pass,
+// using reservoir sampling Algorithm R.
+//
+// Memory use is proportional to k, and every item in seq has the same probability of being
+// selected. If seq has fewer than k items, all of them are returned. The order of the returned
+// items is randomized. Returns nil if k <= 0.
+func ReservoirR[E any](seq iter.Seq[E], k int) []E {
+	if k <= 0 {
+		return nil
+	}
+
+	out := make([]E, 0, k)
+	i := 0
+	for v := range seq {
+		if i < k {
+			out = append(out, v)
+		} else if j := r.IntN(i + 1); j < k {
+			out[j] = v
+		}
+		i++
+	}
+
+	Shuffle(out)
+	return out
+}
+
+// ReservoirL returns up to k items chosen uniformly at random from seq in a single pass,
+// using reservoir sampling Algorithm L.
+//
+// It selects the same way as ReservoirR, but computes how many items to skip between
+// replacements, so it only draws random numbers for items that enter the reservoir.
+// This makes it much faster than ReservoirR for long streams. Returns nil if k <= 0.
+func ReservoirL[E any](seq iter.Seq[E], k int) []E {
+	if k <= 0 {
+		return nil
+	}
+
+	out := make([]E, 0, k)
+	w := 1.0
+	next := 0
+	i := 0
+	for v := range seq {
+		switch {
+		case i < k:
+			out = append(out, v)
+			if i == k-1 {
+				w = math.Exp(math.Log(unitOpen()) / float64(k))
+				next = i + reservoirSkip(w) + 1
+			}
+		case i == next:
+			out[r.IntN(k)] = v
+			w *= math.Exp(math.Log(unitOpen()) / float64(k))
+			next += reservoirSkip(w) + 1
+		}
+		i++
+	}
+
+	Shuffle(out)
+	return out
+}
+
+// ReservoirChan returns up to k items chosen uniformly at random from the values received on ch,
+// reading until ch is closed. It uses Algorithm L. Returns nil if k <= 0.
+func ReservoirChan[E any](ch <-chan E, k int) []E {
+	return ReservoirL(func(yield func(E) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}, k)
+}
+
+// ReservoirLines returns up to k tokens chosen uniformly at random from sc, which are lines
+// unless another split function was set on the scanner. It uses Algorithm L and returns the
+// scanner's error, if any, along with the items sampled so far. Returns nil if k <= 0.
+func ReservoirLines(sc *bufio.Scanner, k int) ([]string, error) {
+	out := ReservoirL(func(yield func(string) bool) {
+		for sc.Scan() {
+			if !yield(sc.Text()) {
+				return
+			}
+		}
+	}, k)
+	return out, sc.Err()
+}
+
+// WeightedReservoir returns up to k items chosen from seq without replacement, where each
+// item's chance of selection is proportional to its weight, using the Efraimidis-Spirakis
+// A-Res algorithm.
+//
+// The selection matches drawing k items one after another from a Lottery and removing each
+// drawn item, and the returned items are in that draw order. Items with weight <= 0 are never
+// selected. Memory use is proportional to k. Returns nil if k <= 0.
+func WeightedReservoir[E any](seq iter.Seq2[E, float64], k int) []E {
+	if k <= 0 {
+		return nil
+	}
+
+	h := make(weightedHeap[E], 0, k)
+	for v, w := range seq {
+		if !(w > 0) {
+			continue
+		}
+		key := math.Log(unitOpen()) / w
+		if len(h) < k {
+			heap.Push(&h, weightedItem[E]{value: v, key: key})
+		} else if key > h[0].key {
+			h[0] = weightedItem[E]{value: v, key: key}
+			heap.Fix(&h, 0)
+		}
+	}
+	return h.sorted()
+}
+
+// WeightedReservoirExpJ selects items like WeightedReservoir, but uses the exponential jumps
+// of the A-ExpJ algorithm to skip over items that cannot enter the reservoir, so it only draws
+// random numbers for items that are selected. Returns nil if k <= 0.
+func WeightedReservoirExpJ[E any](seq iter.Seq2[E, float64], k int) []E {
+	if k <= 0 {
+		return nil
+	}
+
+	h := make(weightedHeap[E], 0, k)
+	jump := 0.0
+	for v, w := range seq {
+		if !(w > 0) {
+			continue
+		}
+		if len(h) < k {
+			heap.Push(&h, weightedItem[E]{value: v, key: math.Log(unitOpen()) / w})
+			if len(h) == k {
+				jump = math.Log(unitOpen()) / h[0].key
+			}
+			continue
+		}
+
+		jump -= w
+		if jump > 0 {
+			continue
+		}
+
+		// The new key is drawn conditionally on beating the current minimum.
+		t := math.Exp(w * h[0].key)
+		u := t + (1-t)*r.Float64()
+		h[0] = weightedItem[E]{value: v, key: math.Log(u) / w}
+		heap.Fix(&h, 0)
+		jump = math.Log(unitOpen()) / h[0].key
+	}
+	return h.sorted()
+}
+
+// weightedItem is an item in a weighted reservoir with its log-space key log(u)/w.
+type weightedItem[E any] struct {
+	value E
+	key   float64
+}
+
+// weightedHeap is a min-heap of weighted items ordered by key.
+type weightedHeap[E any] []weightedItem[E]
+
+func (h weightedHeap[E]) Len() int           { return len(h) }
+func (h weightedHeap[E]) Less(i, j int) bool { return h[i].key < h[j].key }
+func (h weightedHeap[E]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *weightedHeap[E]) Push(x any)        { *h = append(*h, x.(weightedItem[E])) }
+
+func (h *weightedHeap[E]) Pop() any {
+	old := *h
+	item := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return item
+}
+
+// sorted returns the values ordered by decreasing key.
+func (h weightedHeap[E]) sorted() []E {
+	slices.SortFunc(h, func(a, b weightedItem[E]) int {
+		return cmp.Compare(b.key, a.key)
+	})
+	out := make([]E, len(h))
+	for i := range h {
+		out[i] = h[i].value
+	}
+	return out
+}
+
+// reservoirSkip returns the number of items Algorithm L skips before the next replacement.
+func reservoirSkip(w float64) int {
+	skip := math.Floor(math.Log(unitOpen()) / math.Log1p(-w))
+	if skip >= math.MaxInt/2 || math.IsNaN(skip) {
+		return math.MaxInt / 2
+	}
+	return int(skip)
+}
+
+// unitOpen returns a random number in (0, 1].
+func unitOpen() float64 {
+	return 1 - r.Float64()
+}
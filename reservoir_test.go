@@ -0,0 +1,260 @@
+package rng
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReservoir(t *testing.T) {
+	algorithms := []struct {
+		name string
+		fn   func(iter.Seq[int], int) []int
+	}{
+		{"R", ReservoirR[int]},
+		{"L", ReservoirL[int]},
+	}
+
+	for _, alg := range algorithms {
+		t.Run(alg.name, func(t *testing.T) {
+			t.Run("returns nil for k <= 0", func(t *testing.T) {
+				if got := alg.fn(slices.Values([]int{1, 2, 3}), 0); got != nil {
+					t.Errorf("expected nil, got %v", got)
+				}
+			})
+
+			t.Run("short stream returns everything", func(t *testing.T) {
+				got := alg.fn(slices.Values([]int{1, 2, 3}), 5)
+				slices.Sort(got)
+				if !slices.Equal(got, []int{1, 2, 3}) {
+					t.Errorf("expected [1 2 3], got %v", got)
+				}
+			})
+
+			t.Run("distinct items", func(t *testing.T) {
+				got := alg.fn(seqRange(100000), 50)
+				if len(got) != 50 {
+					t.Fatalf("expected 50 items, got %d", len(got))
+				}
+				slices.Sort(got)
+				if len(slices.Compact(got)) != 50 {
+					t.Errorf("expected distinct items, got %v", got)
+				}
+			})
+
+			t.Run("uniform inclusion", func(t *testing.T) {
+				counts := make(map[int]int)
+				for range 20000 {
+					for _, v := range alg.fn(seqRange(50), 5) {
+						counts[v]++
+					}
+				}
+				if chi := chiSquare(counts, 50); chi > chiSquareLimit(49) {
+					t.Errorf("inclusion is not uniform (chi-square %f): %v", chi, counts)
+				}
+			})
+
+			t.Run("uniform subsets", func(t *testing.T) {
+				// C(5, 2) = 10 possible subsets.
+				counts := make(map[[2]int]int)
+				for range 20000 {
+					got := alg.fn(seqRange(5), 2)
+					slices.Sort(got)
+					counts[[2]int{got[0], got[1]}]++
+				}
+				if len(counts) != 10 {
+					t.Fatalf("expected 10 subsets, got %d", len(counts))
+				}
+				if chi := chiSquare(counts, 10); chi > chiSquareLimit(9) {
+					t.Errorf("subsets are not uniform (chi-square %f): %v", chi, counts)
+				}
+			})
+		})
+	}
+}
+
+func TestReservoirChan(t *testing.T) {
+	ch := make(chan int)
+	go func() {
+		for i := range 1000 {
+			ch <- i
+		}
+		close(ch)
+	}()
+
+	got := ReservoirChan(ch, 10)
+	if len(got) != 10 {
+		t.Fatalf("expected 10 items, got %d", len(got))
+	}
+	for _, v := range got {
+		if v < 0 || v >= 1000 {
+			t.Errorf("unexpected item %d", v)
+		}
+	}
+}
+
+type failingReader struct {
+	data string
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.data == "" {
+		return 0, errors.New("read failed")
+	}
+	n := copy(p, f.data)
+	f.data = f.data[n:]
+	return n, nil
+}
+
+func TestReservoirLines(t *testing.T) {
+	t.Run("samples lines", func(t *testing.T) {
+		var sb strings.Builder
+		for i := range 1000 {
+			fmt.Fprintf(&sb, "line %d\n", i)
+		}
+
+		got, err := ReservoirLines(bufio.NewScanner(strings.NewReader(sb.String())), 3)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 3 {
+			t.Fatalf("expected 3 lines, got %v", got)
+		}
+		for _, line := range got {
+			if !strings.HasPrefix(line, "line ") {
+				t.Errorf("unexpected line %q", line)
+			}
+		}
+	})
+
+	t.Run("returns scanner error", func(t *testing.T) {
+		got, err := ReservoirLines(bufio.NewScanner(&failingReader{data: "a\nb\n"}), 5)
+		if err == nil {
+			t.Error("expected read error")
+		}
+		if len(got) != 2 {
+			t.Errorf("expected lines read before the error, got %v", got)
+		}
+	})
+}
+
+func seqRange(n int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := range n {
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestWeightedReservoir(t *testing.T) {
+	algorithms := []struct {
+		name string
+		fn   func(iter.Seq2[string, float64], int) []string
+	}{
+		{"A-Res", WeightedReservoir[string]},
+		{"A-ExpJ", WeightedReservoirExpJ[string]},
+	}
+
+	weights := map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4}
+	items := []string{"a", "b", "c", "d", "zero", "negative"}
+	seq := func(yield func(string, float64) bool) {
+		for _, v := range items {
+			w := weights[v]
+			if v == "negative" {
+				w = -1
+			}
+			if !yield(v, w) {
+				return
+			}
+		}
+	}
+
+	// Exact inclusion probabilities for drawing 2 items one after another without replacement.
+	inclusion := make(map[string]float64)
+	for first, w1 := range weights {
+		for second, w2 := range weights {
+			if first == second {
+				continue
+			}
+			p := w1 / 10 * w2 / (10 - w1)
+			inclusion[first] += p
+			inclusion[second] += p
+		}
+	}
+
+	for _, alg := range algorithms {
+		t.Run(alg.name, func(t *testing.T) {
+			t.Run("returns nil for k <= 0", func(t *testing.T) {
+				if got := alg.fn(seq, 0); got != nil {
+					t.Errorf("expected nil, got %v", got)
+				}
+			})
+
+			t.Run("skips non-positive weights", func(t *testing.T) {
+				got := alg.fn(seq, 10)
+				slices.Sort(got)
+				if !slices.Equal(got, []string{"a", "b", "c", "d"}) {
+					t.Errorf("expected only positive weights, got %v", got)
+				}
+			})
+
+			t.Run("inclusion probabilities", func(t *testing.T) {
+				iterations := 40000
+				counts := make(map[string]int)
+				firsts := make(map[string]int)
+				for range iterations {
+					got := alg.fn(seq, 2)
+					if len(got) != 2 || got[0] == got[1] {
+						t.Fatalf("expected 2 distinct items, got %v", got)
+					}
+					firsts[got[0]]++
+					for _, v := range got {
+						counts[v]++
+					}
+				}
+
+				for v, want := range inclusion {
+					if got := float64(counts[v]) / float64(iterations); math.Abs(got-want) > 0.015 {
+						t.Errorf("item %s: expected inclusion %f, got %f", v, want, got)
+					}
+					if got := float64(firsts[v]) / float64(iterations); math.Abs(got-weights[v]/10) > 0.015 {
+						t.Errorf("item %s: expected to be first with probability %f, got %f", v, weights[v]/10, got)
+					}
+				}
+			})
+
+			t.Run("long stream", func(t *testing.T) {
+				long := func(yield func(string, float64) bool) {
+					for i := range 100000 {
+						w := 1.0
+						if i%1000 == 0 {
+							w = 1e6
+						}
+						if !yield(fmt.Sprint(i), w) {
+							return
+						}
+					}
+				}
+
+				heavy := 0
+				for _, v := range alg.fn(long, 100) {
+					var i int
+					fmt.Sscan(v, &i)
+					if i%1000 == 0 {
+						heavy++
+					}
+				}
+				if heavy < 95 {
+					t.Errorf("expected heavy items to dominate the sample, got %d of 100", heavy)
+				}
+			})
+		})
+	}
+}
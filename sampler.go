@@ -0,0 +1,201 @@
+package rng
+
+import (
+	"sync"
+	"time"
+)
+
+const samplerBuckets = 10
+
+// slidingCounter counts events over a sliding window split into samplerBuckets ticks.
+type slidingCounter struct {
+	counts [samplerBuckets]float64
+	tick   int64
+}
+
+func (c *slidingCounter) advance(tick int64) {
+	if tick-c.tick >= samplerBuckets {
+		clear(c.counts[:])
+	} else {
+		for t := c.tick + 1; t <= tick; t++ {
+			c.counts[t%samplerBuckets] = 0
+		}
+	}
+	c.tick = max(c.tick, tick)
+}
+
+func (c *slidingCounter) add(v float64) {
+	c.counts[c.tick%samplerBuckets] += v
+}
+
+func (c *slidingCounter) total() float64 {
+	sum := 0.0
+	for _, v := range c.counts {
+		sum += v
+	}
+	return sum
+}
+
+type samplerKey struct {
+	seen, kept slidingCounter
+}
+
+// AdaptiveSampler is a thread-safe sampler that adjusts its sampling probability to keep
+// a target number of events per second, such as for logs and traces.
+//
+// Events are counted per key over a sliding window. Keys with their own budget keep that
+// many events per second, and the remaining target is shared equally between all other
+// keys that were active during the window. Every key keeps at least its minimum number of
+// events per window, so rare keys are never dropped entirely. Until a whole window has passed
+// since the first event, rates are measured over the time elapsed so far.
+type AdaptiveSampler struct {
+	mu        sync.Mutex
+	target    float64
+	window    time.Duration
+	minPerKey int
+	budgets   map[string]float64
+	keys      map[string]*samplerKey
+	tick      int64
+	start     int64
+	started   bool
+	active    int
+	now       func() time.Time
+}
+
+// NewAdaptiveSampler creates a sampler that aims to keep targetPerSecond events per second,
+// measured over a sliding window. Panics if window is shorter than 10ns.
+func NewAdaptiveSampler(targetPerSecond float64, window time.Duration) *AdaptiveSampler {
+	if window < samplerBuckets {
+		panic("window must be at least 10ns")
+	}
+	return &AdaptiveSampler{
+		target:  targetPerSecond,
+		window:  window,
+		budgets: make(map[string]float64),
+		keys:    make(map[string]*samplerKey),
+		now:     time.Now,
+	}
+}
+
+// SetKeyBudget gives a key its own budget of kept events per second, which is taken out
+// of the overall target.
+func (s *AdaptiveSampler) SetKeyBudget(key string, perSecond float64) *AdaptiveSampler {
+	s.mu.Lock()
+	s.budgets[key] = perSecond
+	s.mu.Unlock()
+	return s
+}
+
+// SetMinPerKey sets the number of events per key that are kept in every window regardless
+// of the budget.
+func (s *AdaptiveSampler) SetMinPerKey(n int) *AdaptiveSampler {
+	s.mu.Lock()
+	s.minPerKey = n
+	s.mu.Unlock()
+	return s
+}
+
+// Sample records an event for the key and reports whether it should be kept.
+func (s *AdaptiveSampler) Sample(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := s.key(key)
+	k.seen.add(1)
+
+	keep := k.kept.total() < float64(s.minPerKey) || s.probability(key, k).Check()
+	if keep {
+		k.kept.add(1)
+	}
+	return keep
+}
+
+// Probability returns the probability with which the next event for the key would be kept,
+// ignoring the per-key minimum. It can be used to weight kept events by 1/p.
+func (s *AdaptiveSampler) Probability(key string) Probability {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advance()
+	k, ok := s.keys[key]
+	if !ok {
+		return 1
+	}
+	return s.probability(key, k)
+}
+
+// probability returns the keep probability of a key. s.mu must be held.
+func (s *AdaptiveSampler) probability(key string, k *samplerKey) Probability {
+	seen := k.seen.total()
+	if seen == 0 {
+		return 1
+	}
+
+	budget, ok := s.budgets[key]
+	if !ok {
+		budget = s.target
+		for _, b := range s.budgets {
+			budget -= b
+		}
+		budget = max(budget, 0) / float64(max(s.active, 1))
+	}
+	span := s.span()
+	if span <= 0 {
+		return 1
+	}
+	return Probability(min(1, budget*span.Seconds()/seen))
+}
+
+// span returns the length of time covered by the counters: the full ticks before the
+// current one plus the part of the current tick that has passed, or the time since the
+// first event if that is shorter. s.mu must be held.
+func (s *AdaptiveSampler) span() time.Duration {
+	tick := s.window / samplerBuckets
+	now := s.now().UnixNano()
+	full := tick*(samplerBuckets-1) + time.Duration(now-s.tick*int64(tick))
+	return min(full, time.Duration(now-s.start))
+}
+
+// key returns the counters of a key, creating them if needed. s.mu must be held.
+func (s *AdaptiveSampler) key(key string) *samplerKey {
+	s.advance()
+
+	k, ok := s.keys[key]
+	if !ok {
+		k = &samplerKey{seen: slidingCounter{tick: s.tick}, kept: slidingCounter{tick: s.tick}}
+		s.keys[key] = k
+	}
+	if k.seen.total() == 0 {
+		if _, budgeted := s.budgets[key]; !budgeted {
+			s.active++
+		}
+	}
+	return k
+}
+
+// advance moves all counters to the current tick, forgetting keys that were idle for a
+// whole window and recounting the active keys. s.mu must be held.
+func (s *AdaptiveSampler) advance() {
+	now := s.now().UnixNano()
+	if !s.started {
+		s.start, s.started = now, true
+	}
+	tick := now / int64(s.window/samplerBuckets)
+	if tick <= s.tick {
+		return
+	}
+	s.tick = tick
+
+	s.active = 0
+	for name, k := range s.keys {
+		k.seen.advance(tick)
+		k.kept.advance(tick)
+		if k.seen.total() == 0 {
+			delete(s.keys, name)
+			continue
+		}
+		if _, budgeted := s.budgets[name]; !budgeted {
+			s.active++
+		}
+	}
+}
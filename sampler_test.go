@@ -0,0 +1,135 @@
+package rng
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestNewAdaptiveSampler(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("expected panic for zero window")
+		}
+	}()
+	NewAdaptiveSampler(10, 0)
+}
+
+func TestAdaptiveSampler(t *testing.T) {
+	// run feeds the sampler with events at the given rates per key for the given duration
+	// and returns the number of kept events per key during the last half of the run.
+	run := func(s *AdaptiveSampler, clock *fakeClock, rates map[string]int, seconds int) map[string]int {
+		kept := make(map[string]int)
+		steps := seconds * 100
+		for step := range steps {
+			clock.t = clock.t.Add(10 * time.Millisecond)
+			for key, rate := range rates {
+				for range rate / 100 {
+					if s.Sample(key) && step >= steps/2 {
+						kept[key]++
+					}
+				}
+			}
+		}
+		return kept
+	}
+
+	t.Run("keeps everything below target", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewAdaptiveSampler(1000, time.Second)
+		s.now = clock.now
+
+		kept := run(s, clock, map[string]int{"a": 500}, 10)
+		if kept["a"] != 2500 {
+			t.Errorf("expected all 2500 events to be kept, got %d", kept["a"])
+		}
+	})
+
+	t.Run("keeps the target during the first window", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewAdaptiveSampler(100, time.Second)
+		s.now = clock.now
+
+		kept := 0
+		for range 50 {
+			for range 10 {
+				if s.Sample("a") {
+					kept++
+				}
+			}
+			clock.t = clock.t.Add(10 * time.Millisecond)
+		}
+		// 1000 events per second for half a second should keep about 50.
+		if kept < 35 || kept > 80 {
+			t.Errorf("expected about 50 events kept in the first half second, got %d", kept)
+		}
+	})
+
+	t.Run("adapts to spikes", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewAdaptiveSampler(100, time.Second)
+		s.now = clock.now
+
+		for _, rate := range []int{1000, 20000} {
+			kept := run(s, clock, map[string]int{"a": rate}, 10)
+			if perSecond := float64(kept["a"]) / 5; math.Abs(perSecond-100) > 15 {
+				t.Errorf("rate %d: expected about 100 kept per second, got %f", rate, perSecond)
+			}
+		}
+
+		if p := s.Probability("a"); math.Abs(float64(p)-0.005) > 0.001 {
+			t.Errorf("expected probability near 0.005, got %v", p)
+		}
+		if p := s.Probability("unknown"); p != 1 {
+			t.Errorf("expected probability 1 for unknown key, got %v", p)
+		}
+	})
+
+	t.Run("shares target between keys", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewAdaptiveSampler(200, time.Second).SetKeyBudget("/health", 10)
+		s.now = clock.now
+
+		kept := run(s, clock, map[string]int{"/a": 5000, "/b": 1000, "/health": 10000}, 40)
+		for key, want := range map[string]float64{"/a": 95, "/b": 95, "/health": 10} {
+			// Allow 20%, or five Poisson standard errors for keys with few kept events.
+			tolerance := max(want*0.2, 5*math.Sqrt(want*20)/20)
+			if perSecond := float64(kept[key]) / 20; math.Abs(perSecond-want) > tolerance {
+				t.Errorf("%s: expected about %f kept per second, got %f", key, want, perSecond)
+			}
+		}
+	})
+
+	t.Run("guarantees minimum for rare keys", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewAdaptiveSampler(1, time.Second).SetMinPerKey(5)
+		s.now = clock.now
+
+		kept := run(s, clock, map[string]int{"rare": 100, "busy": 10000}, 10)
+		if kept["rare"] < 25 {
+			t.Errorf("expected at least 5 rare events per second, got %d in 5 seconds", kept["rare"])
+		}
+	})
+
+	t.Run("forgets idle keys", func(t *testing.T) {
+		clock := &fakeClock{t: time.Unix(0, 0)}
+		s := NewAdaptiveSampler(10, time.Second)
+		s.now = clock.now
+
+		s.Sample("old")
+		clock.t = clock.t.Add(2 * time.Second)
+		s.Sample("new")
+		if _, ok := s.keys["old"]; ok {
+			t.Error("expected idle key to be forgotten")
+		}
+		if s.active != 1 {
+			t.Errorf("expected 1 active key, got %d", s.active)
+		}
+	})
+}
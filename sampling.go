@@ -0,0 +1,99 @@
+package rng
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrTooManyRejections is returned by Rejection when no candidate is accepted
+// within the allowed number of attempts.
+var ErrTooManyRejections = errors.New("too many rejections")
+
+// InverseCDF draws a sample by inverse transform sampling using the provided quantile function.
+//
+// The quantile function receives a uniform value in [0, 1) and must return the
+// smallest x whose cumulative probability is at least that value.
+func InverseCDF(quantile func(p float64) float64) float64 {
+	return quantile(r.Float64())
+}
+
+// NumericInverseCDF draws a sample by numerically inverting a monotone non-decreasing CDF.
+//
+// The search starts from the bracket [lo, hi], which is widened as needed when the
+// target probability falls outside of it. Bisection stops once the bracket is narrower
+// than the floating point resolution around the result.
+// Returns an error if lo >= hi or if no bracket containing the target can be found.
+func NumericInverseCDF(cdf func(x float64) float64, lo, hi float64) (float64, error) {
+	if !(lo < hi) {
+		return 0, fmt.Errorf("lo must be less than hi")
+	}
+
+	u := r.Float64()
+	for i := 0; cdf(lo) > u; i++ {
+		if i == 64 {
+			return 0, fmt.Errorf("could not find a lower bound for p=%g", u)
+		}
+		lo -= hi - lo
+	}
+	for i := 0; cdf(hi) < u; i++ {
+		if i == 64 {
+			return 0, fmt.Errorf("could not find an upper bound for p=%g", u)
+		}
+		hi += hi - lo
+	}
+
+	for range 200 {
+		mid := lo + (hi-lo)/2
+		if mid <= lo || mid >= hi {
+			break
+		}
+		if cdf(mid) < u {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
+
+// Envelope is a proposal distribution used for rejection sampling.
+//
+// Sample draws a candidate from the proposal, and Density returns the scaled proposal
+// density M·g(x) at x, which must be greater than or equal to the target density everywhere.
+type Envelope struct {
+	Sample  func() float64
+	Density func(x float64) float64
+}
+
+// UniformEnvelope returns an envelope over [min, max) with a constant height of bound.
+// bound must be at least the maximum of the target density on [min, max).
+func UniformEnvelope(min, max, bound float64) Envelope {
+	return Envelope{
+		Sample:  func() float64 { return min + r.Float64()*(max-min) },
+		Density: func(float64) float64 { return bound },
+	}
+}
+
+// Rejection draws a sample from an unnormalized density using rejection sampling.
+//
+// Candidates are drawn from env and accepted with probability density(x) / env.Density(x).
+// Returns ErrTooManyRejections if maxRejections candidates are rejected in a row,
+// which usually means the envelope is far too loose or does not cover the density.
+func Rejection(density func(x float64) float64, env Envelope, maxRejections int) (float64, error) {
+	if maxRejections <= 0 {
+		return 0, fmt.Errorf("maxRejections must be greater than 0")
+	}
+
+	for range maxRejections {
+		x := env.Sample()
+		h := env.Density(x)
+		if h <= 0 || math.IsNaN(h) {
+			continue
+		}
+		if r.Float64()*h < density(x) {
+			return x, nil
+		}
+	}
+	return 0, ErrTooManyRejections
+}
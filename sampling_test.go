@@ -0,0 +1,104 @@
+package rng
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestInverseCDF(t *testing.T) {
+	t.Run("exponential quantile", func(t *testing.T) {
+		quantile := func(p float64) float64 { return -math.Log1p(-p) / 2 }
+		sum := 0.0
+		iterations := 20000
+		for range iterations {
+			v := InverseCDF(quantile)
+			if v < 0 {
+				t.Fatalf("expected non-negative sample, got %f", v)
+			}
+			sum += v
+		}
+
+		mean := sum / float64(iterations)
+		if math.Abs(mean-0.5) > 0.03 {
+			t.Errorf("expected mean near 0.5, got %f", mean)
+		}
+	})
+}
+
+func TestNumericInverseCDF(t *testing.T) {
+	t.Run("returns error for invalid bracket", func(t *testing.T) {
+		_, err := NumericInverseCDF(func(x float64) float64 { return x }, 1, 1)
+		if err == nil {
+			t.Error("expected error for lo == hi")
+		}
+	})
+
+	t.Run("uniform cdf stays in bounds", func(t *testing.T) {
+		cdf := func(x float64) float64 { return math.Min(1, math.Max(0, (x-2)/3)) }
+		for range 1000 {
+			v, err := NumericInverseCDF(cdf, 2, 5)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v < 2 || v > 5 {
+				t.Fatalf("sample %f out of range [2, 5]", v)
+			}
+		}
+	})
+
+	t.Run("widens a bracket that is too narrow", func(t *testing.T) {
+		cdf := func(x float64) float64 { return 1 - math.Exp(-math.Max(0, x)) }
+		sum := 0.0
+		iterations := 20000
+		for range iterations {
+			v, err := NumericInverseCDF(cdf, 0, 0.1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			sum += v
+		}
+
+		mean := sum / float64(iterations)
+		if math.Abs(mean-1) > 0.05 {
+			t.Errorf("expected mean near 1, got %f", mean)
+		}
+	})
+}
+
+func TestRejection(t *testing.T) {
+	t.Run("returns error for non-positive maxRejections", func(t *testing.T) {
+		_, err := Rejection(func(float64) float64 { return 1 }, UniformEnvelope(0, 1, 1), 0)
+		if err == nil {
+			t.Error("expected error for maxRejections=0")
+		}
+	})
+
+	t.Run("triangular density", func(t *testing.T) {
+		density := func(x float64) float64 { return x } // unnormalized, mean 2/3 on [0, 1)
+		sum := 0.0
+		iterations := 20000
+		for range iterations {
+			v, err := Rejection(density, UniformEnvelope(0, 1, 1), 1000)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v < 0 || v >= 1 {
+				t.Fatalf("sample %f out of range [0, 1)", v)
+			}
+			sum += v
+		}
+
+		mean := sum / float64(iterations)
+		if math.Abs(mean-2.0/3) > 0.02 {
+			t.Errorf("expected mean near 0.667, got %f", mean)
+		}
+	})
+
+	t.Run("gives up after maxRejections", func(t *testing.T) {
+		_, err := Rejection(func(float64) float64 { return 0 }, UniformEnvelope(0, 1, 1), 10)
+		if !errors.Is(err, ErrTooManyRejections) {
+			t.Errorf("expected ErrTooManyRejections, got %v", err)
+		}
+	})
+}
@@ -2,6 +2,7 @@ package rng
 
 import (
 	"fmt"
+	"slices"
 )
 
 // Pick returns a random element from the provided slice.
@@ -15,27 +16,26 @@ func Pick[E any](in []E) E {
 
 // PickN returns n randomly selected elements from the input slice.
 //
-// Elements may be duplicated if n is greater than the length of the input slice.
+// Each element is picked independently and uniformly from the whole slice, so the result
+// may contain duplicates. Use PickNDistinct to pick without replacement.
 // Returns nil if n is less than or equal to 0 or if the input slice is empty.
-// The selection uses a random permutation to determine which elements to pick.
 func PickN[E any](in []E, n int) []E {
 	if n <= 0 || len(in) == 0 {
 		return nil
 	}
 
 	out := make([]E, n)
-	p := r.Perm(n)
-	ln := len(in)
 	for i := range n {
-		out[i] = in[p[i]%ln]
+		out[i] = in[r.IntN(len(in))]
 	}
 	return out
 }
 
 // PickNDistinct returns n distinct elements randomly selected from the given slice.
 //
-// The function uses Fisher-Yates shuffle algorithm to ensure each element has an equal
-// probability of being selected. The order of elements in the returned slice is randomized.
+// The function uses a partial Fisher-Yates shuffle to ensure each element has an equal
+// probability of being selected, so the cost is proportional to n rather than to the length
+// of the slice. The order of elements in the returned slice is randomized.
 // Returns nil if n <= 0. Panics if n is greater than the length of the input slice.
 func PickNDistinct[E any](slice []E, n int) []E {
 	if n <= 0 {
@@ -45,15 +45,46 @@ func PickNDistinct[E any](slice []E, n int) []E {
 		panic("n must be less than the length of the slice")
 	}
 
-	perm := r.Perm(len(slice))
 	result := make([]E, n)
-	for i := range n {
-		result[i] = slice[perm[i]]
+	for i, j := range PickNIndices(len(slice), n) {
+		result[i] = slice[j]
 	}
 
 	return result
 }
 
+// PickNIndices returns n distinct indices randomly selected from [0, size).
+//
+// It runs a partial Fisher-Yates shuffle over a virtual array of indices, keeping only the
+// swapped positions in a map, so time and memory are proportional to n however large size is.
+// The order of the returned indices is randomized.
+// Returns nil if n <= 0. Panics if n is greater than size.
+func PickNIndices(size, n int) []int {
+	if n <= 0 {
+		return nil
+	}
+	if n > size {
+		panic("n must be less than or equal to size")
+	}
+
+	out := make([]int, n)
+	swapped := make(map[int]int, n)
+	for i := range n {
+		j := i + r.IntN(size-i)
+		vj, ok := swapped[j]
+		if !ok {
+			vj = j
+		}
+		vi, ok := swapped[i]
+		if !ok {
+			vi = i
+		}
+		out[i] = vj
+		swapped[j] = vi
+	}
+	return out
+}
+
 // PickNUnique returns n randomly selected unique elements from the given slice.
 //
 // It first removes duplicates from the input slice, then randomly picks n distinct elements.
@@ -83,3 +114,66 @@ func Shuffle[T any](in []T) {
 		in[i], in[j] = in[j], in[i]
 	})
 }
+
+// ShuffleN randomly rearranges the slice in place so that its first k elements are a uniformly
+// random ordered selection of k elements of the whole slice.
+//
+// Only k swaps are made, so the cost is proportional to k rather than to the length of the slice.
+// The remaining elements end up after the first k in an unspecified order.
+// If k <= 0, no shuffling is performed; if k >= len(in), the whole slice is shuffled.
+func ShuffleN[T any](in []T, k int) {
+	k = min(k, len(in)-1)
+	for i := range k {
+		j := i + r.IntN(len(in)-i)
+		in[i], in[j] = in[j], in[i]
+	}
+}
+
+// Derange randomly rearranges the elements of the slice in place so that no element stays in its
+// original position, such as for gift exchanges. Every such arrangement is equally likely.
+//
+// It runs Fisher-Yates shuffles from the end of the slice and restarts as soon as a position keeps
+// its original element, which takes about e attempts on average.
+// Returns an error if the slice has exactly one element, which cannot be deranged.
+func Derange[T any](in []T) error {
+	if len(in) == 1 {
+		return fmt.Errorf("a slice with one element has no derangement")
+	}
+
+	perm := make([]int, len(in))
+	for !tryDerangement(perm) {
+	}
+
+	orig := slices.Clone(in)
+	for i, j := range perm {
+		in[i] = orig[j]
+	}
+	return nil
+}
+
+// tryDerangement fills perm with a random permutation from a Fisher-Yates shuffle run from the
+// end, and reports false as soon as a position is fixed, since that position is final.
+func tryDerangement(perm []int) bool {
+	for i := range perm {
+		perm[i] = i
+	}
+	for i := len(perm) - 1; i >= 0; i-- {
+		j := r.IntN(i + 1)
+		perm[i], perm[j] = perm[j], perm[i]
+		if perm[i] == i {
+			return false
+		}
+	}
+	return true
+}
+
+// ShuffleCycle randomly rearranges the elements of the slice in place using Sattolo's algorithm,
+// so that the arrangement is a single cycle: following each element to the position of the
+// element it replaced visits the whole slice. Every such arrangement is equally likely, and no
+// element stays in its original position if the slice has 2 or more elements.
+func ShuffleCycle[T any](in []T) {
+	for i := len(in) - 1; i > 0; i-- {
+		j := r.IntN(i)
+		in[i], in[j] = in[j], in[i]
+	}
+}
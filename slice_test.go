@@ -2,7 +2,9 @@ package rng
 
 import (
 	"fmt"
+	"math"
 	"slices"
+	"strings"
 	"testing"
 )
 
@@ -145,6 +147,40 @@ func TestPickN(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("picks uniformly from the whole slice", func(t *testing.T) {
+		slice := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+		counts := make(map[int]int)
+		for range 20000 {
+			for _, v := range PickN(slice, 3) {
+				counts[v]++
+			}
+		}
+
+		if len(counts) != len(slice) {
+			t.Fatalf("expected every element to be picked, got %v", counts)
+		}
+		if chi := chiSquare(counts, len(slice)); chi > chiSquareLimit(len(slice)-1) {
+			t.Errorf("counts are not uniform (chi-square %f): %v", chi, counts)
+		}
+	})
+
+	t.Run("picks are independent", func(t *testing.T) {
+		// With replacement, all four picks from two elements are equal with probability 2/16.
+		slice := []string{"a", "b"}
+		outcomes := make(map[string]int)
+		iterations := 16000
+		for range iterations {
+			outcomes[strings.Join(PickN(slice, 4), "")]++
+		}
+
+		if len(outcomes) != 16 {
+			t.Fatalf("expected all 16 outcomes, got %d", len(outcomes))
+		}
+		if chi := chiSquare(outcomes, 16); chi > chiSquareLimit(15) {
+			t.Errorf("outcomes are not uniform (chi-square %f): %v", chi, outcomes)
+		}
+	})
 }
 
 func TestPickNDistinct(t *testing.T) {
@@ -288,6 +324,63 @@ func TestPickNDistinct(t *testing.T) {
 	})
 }
 
+func TestPickNIndices(t *testing.T) {
+	t.Run("returns nil for n <= 0", func(t *testing.T) {
+		if got := PickNIndices(10, 0); got != nil {
+			t.Errorf("expected nil for n=0, got %v", got)
+		}
+	})
+
+	t.Run("panics when n > size", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic when n > size")
+			}
+		}()
+		PickNIndices(3, 4)
+	})
+
+	t.Run("distinct indices from a huge range", func(t *testing.T) {
+		size := 1 << 50
+		got := PickNIndices(size, 10)
+		seen := make(map[int]bool)
+		for _, v := range got {
+			if v < 0 || v >= size {
+				t.Fatalf("index %d out of range [0, %d)", v, size)
+			}
+			if seen[v] {
+				t.Fatalf("duplicate index %d in %v", v, got)
+			}
+			seen[v] = true
+		}
+	})
+
+	t.Run("all indices when n == size", func(t *testing.T) {
+		got := PickNIndices(100, 100)
+		slices.Sort(got)
+		for i, v := range got {
+			if v != i {
+				t.Fatalf("expected a permutation of [0, 100), got %v", got)
+			}
+		}
+	})
+
+	t.Run("ordered picks are uniform", func(t *testing.T) {
+		// 4·3 = 12 ordered pairs of distinct indices.
+		counts := make(map[[2]int]int)
+		for range 24000 {
+			got := PickNIndices(4, 2)
+			counts[[2]int{got[0], got[1]}]++
+		}
+		if len(counts) != 12 {
+			t.Fatalf("expected 12 outcomes, got %d", len(counts))
+		}
+		if chi := chiSquare(counts, 12); chi > chiSquareLimit(11) {
+			t.Errorf("outcomes are not uniform (chi-square %f): %v", chi, counts)
+		}
+	})
+}
+
 func TestPickNUnique(t *testing.T) {
 	t.Run("returns error for n <= 0", func(t *testing.T) {
 		slice := []int{1, 2, 3}
@@ -671,3 +764,178 @@ func TestShuffle(t *testing.T) {
 		}
 	})
 }
+
+// chiSquare returns the chi-square statistic of the observed counts against a uniform
+// distribution over the given number of cells. Cells missing from counts count as 0.
+func chiSquare[K comparable](counts map[K]int, cells int) float64 {
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+
+	expected := float64(total) / float64(cells)
+	chi := float64(cells-len(counts)) * expected
+	for _, c := range counts {
+		d := float64(c) - expected
+		chi += d * d / expected
+	}
+	return chi
+}
+
+// chiSquareLimit returns the value that a chi-square statistic with df degrees of freedom
+// exceeds with probability about one in a million, using the Wilson-Hilferty approximation.
+// The suite runs many uniformity checks, so a tighter limit would make it flaky.
+func chiSquareLimit(df int) float64 {
+	const z = 4.75 // standard normal quantile of 1 - 1e-6
+	k := float64(df)
+	h := 2 / (9 * k)
+	return k * math.Pow(1-h+z*math.Sqrt(h), 3)
+}
+
+// permutationsOf returns every permutation of [0, n).
+func permutationsOf(n int) [][]int {
+	if n == 0 {
+		return [][]int{{}}
+	}
+	var out [][]int
+	for _, p := range permutationsOf(n - 1) {
+		for i := 0; i <= len(p); i++ {
+			out = append(out, slices.Insert(slices.Clone(p), i, n-1))
+		}
+	}
+	return out
+}
+
+func formatInts(p []int) string { return fmt.Sprint(p) }
+
+// isSingleCycle reports whether following p from 0 visits every index.
+func isSingleCycle(p []int) bool {
+	i, steps := p[0], 1
+	for i != 0 {
+		i = p[i]
+		steps++
+	}
+	return steps == len(p)
+}
+
+// checkOutcomes runs shuffle on [0, n) and checks that exactly the permutations accepted by
+// valid occur, all about equally often.
+func checkOutcomes(t *testing.T, n int, key func([]int) string, valid func([]int) bool, shuffle func([]int)) {
+	t.Helper()
+
+	want := make(map[string]bool)
+	for _, p := range permutationsOf(n) {
+		if valid(p) {
+			want[key(p)] = true
+		}
+	}
+
+	checkUniform(t, want, func() string {
+		p := seqSlice(n)
+		shuffle(p)
+		return key(p)
+	})
+}
+
+// checkUniform draws outcomes and checks that exactly the wanted ones occur, all about equally often.
+func checkUniform(t *testing.T, want map[string]bool, draw func() string) {
+	t.Helper()
+
+	counts := make(map[string]int)
+	for range 2000 * len(want) {
+		k := draw()
+		if !want[k] {
+			t.Fatalf("unexpected outcome %s", k)
+		}
+		counts[k]++
+	}
+	if len(counts) != len(want) {
+		t.Errorf("expected %d distinct outcomes, got %d", len(want), len(counts))
+	}
+	if stat := chiSquare(counts, len(want)); stat > chiSquareLimit(len(want)-1) {
+		t.Errorf("outcomes are not uniform: chi-square %f, counts %v", stat, counts)
+	}
+}
+
+func TestShuffleN(t *testing.T) {
+	t.Run("k out of range", func(t *testing.T) {
+		ShuffleN([]int{}, 3)
+		slice := []int{1, 2, 3}
+		ShuffleN(slice, 0)
+		if !slices.Equal(slice, []int{1, 2, 3}) {
+			t.Errorf("expected no change for k=0, got %v", slice)
+		}
+		ShuffleN(slice, 10)
+		slices.Sort(slice)
+		if !slices.Equal(slice, []int{1, 2, 3}) {
+			t.Errorf("expected all elements preserved, got %v", slice)
+		}
+	})
+
+	t.Run("every prefix equally likely", func(t *testing.T) {
+		for _, k := range []int{1, 2, 3} {
+			prefix := func(p []int) string { return fmt.Sprint(p[:k]) }
+			checkOutcomes(t, 5, prefix, func([]int) bool { return true }, func(p []int) {
+				ShuffleN(p, k)
+				if !slices.Equal(slices.Sorted(slices.Values(p)), seqSlice(5)) {
+					t.Fatalf("elements lost: %v", p)
+				}
+			})
+		}
+	})
+
+	t.Run("full shuffle", func(t *testing.T) {
+		checkOutcomes(t, 4, formatInts, func([]int) bool { return true }, func(p []int) { ShuffleN(p, 4) })
+	})
+}
+
+func TestDerange(t *testing.T) {
+	t.Run("single element returns error", func(t *testing.T) {
+		if err := Derange([]int{1}); err == nil {
+			t.Error("expected error for a single element")
+		}
+	})
+
+	t.Run("empty slice", func(t *testing.T) {
+		if err := Derange([]int{}); err != nil {
+			t.Errorf("unexpected error %v", err)
+		}
+	})
+
+	t.Run("every derangement equally likely", func(t *testing.T) {
+		noFixedPoint := func(p []int) bool {
+			for i, v := range p {
+				if i == v {
+					return false
+				}
+			}
+			return true
+		}
+		// There are 1, 2, 9 and 44 derangements of 2 to 5 elements.
+		for n := 2; n <= 5; n++ {
+			checkOutcomes(t, n, formatInts, noFixedPoint, func(p []int) {
+				if err := Derange(p); err != nil {
+					t.Fatal(err)
+				}
+			})
+		}
+	})
+}
+
+func TestShuffleCycle(t *testing.T) {
+	t.Run("short slices", func(t *testing.T) {
+		ShuffleCycle([]int{})
+		slice := []int{7}
+		ShuffleCycle(slice)
+		if slice[0] != 7 {
+			t.Errorf("expected single element unchanged, got %v", slice)
+		}
+	})
+
+	t.Run("every cycle equally likely", func(t *testing.T) {
+		// There are (n-1)! cyclic permutations of n elements.
+		for n := 2; n <= 5; n++ {
+			checkOutcomes(t, n, formatInts, isSingleCycle, ShuffleCycle[int])
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package rng
+
+import (
+	"slices"
+)
+
+// Fold is one round of k-fold cross-validation.
+type Fold[E any] struct {
+	Train []E
+	Test  []E
+}
+
+// Split randomly divides the elements of the slice into partitions whose sizes are proportional
+// to the given fractions, such as Split(data, 0.8, 0.1, 0.1).
+//
+// Fractions are relative, so Split(data, 8, 1, 1) gives the same sizes. Every element ends up in
+// exactly one partition, with fractional sizes rounded at random so that the expected size of
+// every partition is exact. The input slice is not modified. Panics if no fractions are given,
+// if any fraction is negative, or if all fractions are 0.
+func Split[E any](in []E, fractions ...float64) [][]E {
+	checkFractions(fractions)
+
+	shuffled := slices.Clone(in)
+	Shuffle(shuffled)
+	return splitSizes(shuffled, splitCounts(len(in), fractions))
+}
+
+// SplitSizes randomly divides the elements of the slice into partitions of the given sizes.
+// Elements beyond the sum of the sizes are left out. The input slice is not modified.
+// Panics if any size is negative or if the sizes add up to more than the length of the slice.
+func SplitSizes[E any](in []E, sizes ...int) [][]E {
+	total := 0
+	for _, s := range sizes {
+		if s < 0 {
+			panic("sizes must be non-negative")
+		}
+		total += s
+	}
+	if total > len(in) {
+		panic("sizes must not add up to more than the length of the slice")
+	}
+
+	return splitSizes(PickNDistinct(in, total), sizes)
+}
+
+// KFold randomly divides the elements of the slice into k folds of nearly equal size and returns,
+// for each fold, the fold as the test set and the other elements as the training set.
+// The input slice is not modified. Panics if k < 2 or k is greater than the length of the slice.
+func KFold[E any](in []E, k int) []Fold[E] {
+	if k < 2 || k > len(in) {
+		panic("k must be between 2 and the length of the slice")
+	}
+
+	fractions := make([]float64, k)
+	for i := range fractions {
+		fractions[i] = 1
+	}
+	parts := Split(in, fractions...)
+
+	out := make([]Fold[E], k)
+	for i := range parts {
+		train := make([]E, 0, len(in)-len(parts[i]))
+		for j := range parts {
+			if j != i {
+				train = append(train, parts[j]...)
+			}
+		}
+		out[i] = Fold[E]{Train: train, Test: parts[i]}
+	}
+	return out
+}
+
+// SplitGroups randomly divides the elements of the slice into partitions like Split, but keeps all
+// elements with the same key in the same partition, for example all events of one user.
+//
+// Groups are assigned greedily in random order, each to the partition that is furthest below its
+// target size. This is an approximation: with many small groups the sizes come close to the
+// fractions, but a few large groups can leave them further off than the best possible assignment.
+// Elements keep their relative order within each group. Panics under the same conditions as Split.
+func SplitGroups[K comparable, E any](in []E, key func(E) K, fractions ...float64) [][]E {
+	checkFractions(fractions)
+
+	keys, groups := groupBy(in, key)
+	Shuffle(keys)
+
+	total := 0.0
+	for _, f := range fractions {
+		total += f
+	}
+
+	out := make([][]E, len(fractions))
+	for _, k := range keys {
+		best, bestDeficit := 0, 0.0
+		for i, f := range fractions {
+			deficit := f/total*float64(len(in)) - float64(len(out[i]))
+			if i == 0 || deficit > bestDeficit {
+				best, bestDeficit = i, deficit
+			}
+		}
+		out[best] = append(out[best], groups[k]...)
+	}
+	return out
+}
+
+// SplitStratified randomly divides the elements of the slice into partitions like Split, but keeps
+// the proportion of each label the same in every partition, up to rounding.
+//
+// Labels are laid out one after another in random order, and positions are dealt to partitions so
+// that every stretch of the layout is divided in proportion to the fractions. Partition sizes
+// therefore match Split, and each label's count in every partition differs from its exact share
+// by less than 2, however small the labels are. The order of elements within each partition is
+// randomized. Panics under the same conditions as Split.
+func SplitStratified[K comparable, E any](in []E, label func(E) K, fractions ...float64) [][]E {
+	checkFractions(fractions)
+
+	keys, groups := groupBy(in, label)
+	Shuffle(keys)
+	schedule := splitSchedule(splitCounts(len(in), fractions))
+
+	out := make([][]E, len(fractions))
+	pos := 0
+	for _, k := range keys {
+		group := groups[k]
+		Shuffle(group)
+		for _, v := range group {
+			out[schedule[pos]] = append(out[schedule[pos]], v)
+			pos++
+		}
+	}
+	for i := range out {
+		Shuffle(out[i])
+	}
+	return out
+}
+
+func checkFractions(fractions []float64) {
+	if len(fractions) == 0 {
+		panic("at least one fraction is required")
+	}
+	total := 0.0
+	for _, f := range fractions {
+		if !(f >= 0) {
+			panic("fractions must be non-negative")
+		}
+		total += f
+	}
+	if total == 0 {
+		panic("fractions must not all be 0")
+	}
+}
+
+// splitCounts divides n elements in proportion to fractions.
+func splitCounts(n int, fractions []float64) []int {
+	sizes := make([]int, len(fractions))
+	for i := range sizes {
+		sizes[i] = n
+	}
+	return allocate(sizes, fractions, n)
+}
+
+// splitSchedule returns the partition of every position when positions are dealt to partitions
+// of the given sizes one at a time, each to the partition furthest behind its share so far.
+// Every prefix, and so every stretch, of the schedule is divided in proportion to the sizes
+// to within one position per partition at each end.
+func splitSchedule(sizes []int) []int {
+	n := 0
+	for _, s := range sizes {
+		n += s
+	}
+
+	out := make([]int, n)
+	dealt := make([]int, len(sizes))
+	for t := range n {
+		best, bestDeficit := -1, 0
+		for i, s := range sizes {
+			// The deficit s·(t+1)/n - dealt is compared after scaling by n.
+			if dealt[i] == s {
+				continue
+			}
+			if deficit := s*(t+1) - dealt[i]*n; best < 0 || deficit > bestDeficit {
+				best, bestDeficit = i, deficit
+			}
+		}
+		out[t] = best
+		dealt[best]++
+	}
+	return out
+}
+
+// splitSizes cuts consecutive partitions of the given sizes from in. The partitions share
+// memory with in but have their capacity limited, so appending to one never affects another.
+func splitSizes[E any](in []E, sizes []int) [][]E {
+	out := make([][]E, len(sizes))
+	start := 0
+	for i, s := range sizes {
+		out[i] = in[start : start+s : start+s]
+		start += s
+	}
+	return out
+}
@@ -0,0 +1,271 @@
+package rng
+
+import (
+	"slices"
+	"testing"
+)
+
+func sortedConcat[E any](parts [][]E, cmp func(a, b E) int) []E {
+	var out []E
+	for _, p := range parts {
+		out = append(out, p...)
+	}
+	slices.SortFunc(out, cmp)
+	return out
+}
+
+func compareInts(a, b int) int { return a - b }
+
+func TestSplit(t *testing.T) {
+	t.Run("panics for invalid fractions", func(t *testing.T) {
+		for _, fractions := range [][]float64{nil, {0, 0}, {0.5, -0.1}} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("expected panic for fractions %v", fractions)
+					}
+				}()
+				Split([]int{1, 2, 3}, fractions...)
+			}()
+		}
+	})
+
+	t.Run("sizes follow fractions", func(t *testing.T) {
+		in := seqSlice(100)
+		parts := Split(in, 0.8, 0.1, 0.1)
+		if len(parts) != 3 || len(parts[0]) != 80 || len(parts[1]) != 10 || len(parts[2]) != 10 {
+			t.Fatalf("expected sizes 80/10/10, got %d/%d/%d", len(parts[0]), len(parts[1]), len(parts[2]))
+		}
+		if !slices.Equal(sortedConcat(parts, compareInts), in) {
+			t.Error("expected every element in exactly one partition")
+		}
+	})
+
+	t.Run("relative fractions and rounding", func(t *testing.T) {
+		parts := Split(seqSlice(10), 1, 1, 1)
+		total := 0
+		for _, p := range parts {
+			if len(p) < 3 || len(p) > 4 {
+				t.Errorf("expected 3 or 4 elements, got %d", len(p))
+			}
+			total += len(p)
+		}
+		if total != 10 {
+			t.Errorf("expected 10 elements in total, got %d", total)
+		}
+	})
+
+	t.Run("input is not modified", func(t *testing.T) {
+		in := seqSlice(20)
+		Split(in, 1, 1)
+		if !slices.Equal(in, seqSlice(20)) {
+			t.Errorf("input was modified: %v", in)
+		}
+	})
+
+	t.Run("appending does not clobber other partitions", func(t *testing.T) {
+		parts := Split(seqSlice(10), 1, 1)
+		second := slices.Clone(parts[1])
+		_ = append(parts[0], -1)
+		if !slices.Equal(parts[1], second) {
+			t.Error("appending to the first partition changed the second")
+		}
+	})
+
+	t.Run("uniform assignment", func(t *testing.T) {
+		const trials = 20000
+		counts := make(map[int]int)
+		for range trials {
+			for _, v := range Split(seqSlice(10), 0.3, 0.7)[0] {
+				counts[v]++
+			}
+		}
+		if stat := chiSquare(counts, 10); stat > chiSquareLimit(9) {
+			t.Errorf("first partition is not uniform: chi-square %f, counts %v", stat, counts)
+		}
+	})
+}
+
+func TestSplitSizes(t *testing.T) {
+	t.Run("panics when sizes exceed the slice", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic")
+			}
+		}()
+		SplitSizes([]int{1, 2, 3}, 2, 2)
+	})
+
+	t.Run("exact sizes with leftovers dropped", func(t *testing.T) {
+		parts := SplitSizes(seqSlice(10), 3, 0, 5)
+		if len(parts[0]) != 3 || len(parts[1]) != 0 || len(parts[2]) != 5 {
+			t.Fatalf("unexpected sizes %d/%d/%d", len(parts[0]), len(parts[1]), len(parts[2]))
+		}
+		all := sortedConcat(parts, compareInts)
+		if len(slices.Compact(all)) != 8 {
+			t.Errorf("expected 8 distinct elements, got %v", all)
+		}
+	})
+}
+
+func TestKFold(t *testing.T) {
+	t.Run("panics for invalid k", func(t *testing.T) {
+		for _, k := range []int{1, 4} {
+			func() {
+				defer func() {
+					if r := recover(); r == nil {
+						t.Errorf("expected panic for k=%d", k)
+					}
+				}()
+				KFold([]int{1, 2, 3}, k)
+			}()
+		}
+	})
+
+	t.Run("test sets cover the slice once", func(t *testing.T) {
+		in := seqSlice(23)
+		folds := KFold(in, 5)
+		if len(folds) != 5 {
+			t.Fatalf("expected 5 folds, got %d", len(folds))
+		}
+
+		var tests [][]int
+		for i, f := range folds {
+			if len(f.Test) < 4 || len(f.Test) > 5 {
+				t.Errorf("fold %d: expected 4 or 5 test elements, got %d", i, len(f.Test))
+			}
+			if !slices.Equal(sortedConcat([][]int{f.Train, f.Test}, compareInts), in) {
+				t.Errorf("fold %d: train and test do not partition the slice", i)
+			}
+			tests = append(tests, f.Test)
+		}
+		if !slices.Equal(sortedConcat(tests, compareInts), in) {
+			t.Error("expected every element in exactly one test set")
+		}
+	})
+}
+
+func TestSplitGroups(t *testing.T) {
+	t.Run("groups stay together", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 50, "us": 30, "apac": 20})
+		parts := SplitGroups(orders, regionOf, 0.5, 0.3, 0.2)
+
+		seen := make(map[string]int)
+		total := 0
+		for i, p := range parts {
+			for _, o := range p {
+				if j, ok := seen[o.region]; ok && j != i {
+					t.Errorf("region %s is split between partitions %d and %d", o.region, j, i)
+				}
+				seen[o.region] = i
+			}
+			total += len(p)
+		}
+		if total != 100 {
+			t.Errorf("expected 100 elements in total, got %d", total)
+		}
+	})
+
+	t.Run("sizes approach fractions", func(t *testing.T) {
+		in := seqSlice(1000)
+		parts := SplitGroups(in, func(v int) int { return v / 10 }, 0.8, 0.2)
+		if len(parts[0]) != 800 || len(parts[1]) != 200 {
+			t.Errorf("expected sizes 800/200, got %d/%d", len(parts[0]), len(parts[1]))
+		}
+		if !slices.Equal(sortedConcat(parts, compareInts), in) {
+			t.Error("expected every element in exactly one partition")
+		}
+	})
+}
+
+func TestSplitStratified(t *testing.T) {
+	t.Run("label proportions are preserved", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 600, "us": 300, "apac": 100})
+		parts := SplitStratified(orders, regionOf, 0.8, 0.2)
+
+		want := []map[string]int{
+			{"eu": 480, "us": 240, "apac": 80},
+			{"eu": 120, "us": 60, "apac": 20},
+		}
+		for i, p := range parts {
+			got := make(map[string]int)
+			for _, o := range p {
+				got[o.region]++
+			}
+			for region, n := range want[i] {
+				if d := got[region] - n; d <= -2 || d >= 2 {
+					t.Errorf("partition %d: expected about %d orders from %s, got %d", i, n, region, got[region])
+				}
+			}
+		}
+	})
+
+	t.Run("many small labels", func(t *testing.T) {
+		tests := []struct {
+			name      string
+			labelSize int
+			fractions []float64
+			want      []int
+		}{
+			{"five per label", 5, []float64{0.8, 0.1, 0.1}, []int{400, 50, 50}},
+			{"one per label", 1, []float64{0.5, 0.5}, []int{50, 50}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				in := seqSlice(100 * tt.labelSize)
+				label := func(v int) int { return v / tt.labelSize }
+				parts := SplitStratified(in, label, tt.fractions...)
+
+				for i, p := range parts {
+					if len(p) != tt.want[i] {
+						t.Errorf("partition %d: expected %d elements, got %d", i, tt.want[i], len(p))
+					}
+					counts := make(map[int]int)
+					for _, v := range p {
+						counts[label(v)]++
+					}
+					for l := range 100 {
+						share := float64(tt.labelSize) * tt.fractions[i]
+						if d := float64(counts[l]) - share; d <= -2 || d >= 2 {
+							t.Errorf("partition %d: label %d has %d elements, expected about %f", i, l, counts[l], share)
+						}
+					}
+				}
+				if !slices.Equal(sortedConcat(parts, compareInts), in) {
+					t.Error("expected every element in exactly one partition")
+				}
+			})
+		}
+	})
+}
+
+func TestSplitSchedule(t *testing.T) {
+	for _, sizes := range [][]int{{8, 1, 1}, {1, 1}, {5, 3, 2, 7}, {0, 4}} {
+		n := 0
+		for _, s := range sizes {
+			n += s
+		}
+		dealt := make([]int, len(sizes))
+		for pos, i := range splitSchedule(sizes) {
+			dealt[i]++
+			for j, s := range sizes {
+				if d := float64(dealt[j]) - float64(s*(pos+1))/float64(n); d <= -1 || d >= 1 {
+					t.Fatalf("sizes %v: partition %d has %d of the first %d positions", sizes, j, dealt[j], pos+1)
+				}
+			}
+		}
+		for j, s := range sizes {
+			if dealt[j] != s {
+				t.Errorf("sizes %v: partition %d dealt %d positions, want %d", sizes, j, dealt[j], s)
+			}
+		}
+	}
+}
+
+func seqSlice(n int) []int {
+	out := make([]int, n)
+	for i := range out {
+		out[i] = i
+	}
+	return out
+}
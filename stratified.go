@@ -0,0 +1,207 @@
+package rng
+
+import (
+	"math"
+)
+
+// Stratum is the sample drawn from one group of a stratified sample.
+type Stratum[K comparable, E any] struct {
+	// Key is the value returned by the key function for every element of the group.
+	Key K
+	// Size is the number of elements of the group in the population.
+	Size int
+	// Sample holds the elements drawn from the group without replacement, in random order.
+	Sample []E
+	// InclusionProbability is the probability that any given element of the group was
+	// drawn, len(Sample) / Size. Weighting each sampled element by its inverse gives
+	// unbiased estimates for the whole population.
+	InclusionProbability float64
+}
+
+// StratifiedFixed groups the elements of the slice by key and draws n elements from every group,
+// or the whole group if it has fewer than n elements.
+//
+// Groups are returned in the order in which their first element appears in the slice.
+// Returns nil if the slice is empty.
+func StratifiedFixed[K comparable, E any](in []E, key func(E) K, n int) []Stratum[K, E] {
+	keys, groups := groupBy(in, key)
+	alloc := make([]int, len(keys))
+	for i := range keys {
+		alloc[i] = min(max(n, 0), len(groups[keys[i]]))
+	}
+	return drawStrata(keys, groups, alloc)
+}
+
+// StratifiedProportional groups the elements of the slice by key and draws the same fraction
+// of every group.
+//
+// The total sample size is fraction·len(in) rounded to the nearest integer, divided between
+// the groups in proportion to their sizes. Fractional shares are rounded at random, so every
+// element has the same chance of being drawn regardless of the order of the groups.
+// Groups are returned in the order in which their first element appears in the slice.
+// Panics if fraction is not within [0, 1].
+func StratifiedProportional[K comparable, E any](in []E, key func(E) K, fraction float64) []Stratum[K, E] {
+	if !(fraction >= 0 && fraction <= 1) {
+		panic("fraction must be between 0 and 1")
+	}
+
+	keys, groups := groupBy(in, key)
+	sizes := make([]int, len(keys))
+	weights := make([]float64, len(keys))
+	for i := range keys {
+		sizes[i] = len(groups[keys[i]])
+		weights[i] = float64(sizes[i])
+	}
+	n := int(math.Round(fraction * float64(len(in))))
+	return drawStrata(keys, groups, allocate(sizes, weights, n))
+}
+
+// StratifiedNeyman groups the elements of the slice by key and draws n elements in total,
+// divided between the groups using Neyman allocation.
+//
+// Each group receives a share proportional to its size times the standard deviation of the
+// measured quantity within it, given by variance, which minimizes the variance of the estimated
+// population mean. Groups missing from variance are treated as having zero variance. A group never
+// receives more than its size; the excess goes to the other groups. If n is at least len(in), every
+// element is drawn. Groups are returned in the order in which their first element appears in the slice.
+func StratifiedNeyman[K comparable, E any](in []E, key func(E) K, n int, variance map[K]float64) []Stratum[K, E] {
+	keys, groups := groupBy(in, key)
+	sizes := make([]int, len(keys))
+	weights := make([]float64, len(keys))
+	for i, k := range keys {
+		sizes[i] = len(groups[k])
+		if v := variance[k]; v > 0 {
+			weights[i] = float64(sizes[i]) * math.Sqrt(v)
+		}
+	}
+	return drawStrata(keys, groups, allocate(sizes, weights, n))
+}
+
+// groupBy splits the slice into groups by key, returning the keys in order of first appearance.
+func groupBy[K comparable, E any](in []E, key func(E) K) ([]K, map[K][]E) {
+	var keys []K
+	groups := make(map[K][]E)
+	for _, v := range in {
+		k := key(v)
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], v)
+	}
+	return keys, groups
+}
+
+func drawStrata[K comparable, E any](keys []K, groups map[K][]E, alloc []int) []Stratum[K, E] {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	out := make([]Stratum[K, E], len(keys))
+	for i, k := range keys {
+		group := groups[k]
+		out[i] = Stratum[K, E]{
+			Key:                  k,
+			Size:                 len(group),
+			Sample:               PickNDistinct(group, alloc[i]),
+			InclusionProbability: float64(alloc[i]) / float64(len(group)),
+		}
+	}
+	return out
+}
+
+// allocate divides n units between groups in proportion to their weights without giving any group
+// more than its size. Groups that would exceed their size are filled and the rest is divided again
+// between the others. Fractional shares are rounded at random, so every group's expected allocation
+// equals its exact share and ties do not depend on the order of the groups. If no group with spare
+// capacity has a positive weight, the rest is divided in proportion to spare capacity.
+func allocate(sizes []int, weights []float64, n int) []int {
+	alloc := make([]int, len(sizes))
+	total := 0
+	for _, s := range sizes {
+		total += s
+	}
+	remaining := min(max(n, 0), total)
+
+	for remaining > 0 {
+		active := make([]int, 0, len(sizes))
+		w := make([]float64, len(sizes))
+		sum := 0.0
+		for i := range sizes {
+			if alloc[i] < sizes[i] && weights[i] > 0 {
+				active = append(active, i)
+				w[i] = weights[i]
+				sum += w[i]
+			}
+		}
+		if sum == 0 {
+			for i := range sizes {
+				if alloc[i] < sizes[i] {
+					active = append(active, i)
+					w[i] = float64(sizes[i] - alloc[i])
+					sum += w[i]
+				}
+			}
+		}
+
+		// Fill groups whose share exceeds their spare capacity and divide again.
+		capped := false
+		share := float64(remaining) / sum
+		for _, i := range active {
+			if share*w[i] >= float64(sizes[i]-alloc[i]) {
+				remaining -= sizes[i] - alloc[i]
+				alloc[i] = sizes[i]
+				capped = true
+			}
+		}
+		if capped {
+			continue
+		}
+
+		quotas := make([]float64, len(sizes))
+		assigned := 0
+		for _, i := range active {
+			quotas[i] = share * w[i]
+			whole := int(quotas[i])
+			alloc[i] += whole
+			assigned += whole
+			quotas[i] -= float64(whole)
+		}
+		roundRandomly(alloc, active, quotas, min(remaining-assigned, len(active)))
+		break
+	}
+	return alloc
+}
+
+// roundRandomly gives one extra unit to extra of the active groups, each with probability equal
+// to its fractional quota, using systematic sampling over the groups in random order.
+// The fractional quotas of the active groups add up to extra.
+func roundRandomly(alloc, active []int, quotas []float64, extra int) {
+	Shuffle(active)
+	got := make([]bool, len(active))
+	u, cumulative := r.Float64(), 0.0
+	for j, i := range active {
+		if extra == 0 {
+			return
+		}
+		cumulative += quotas[i]
+		if cumulative > u {
+			alloc[i]++
+			got[j] = true
+			extra--
+			u++
+		}
+	}
+
+	// Floating point error can leave a unit over; give it to the largest remaining quota.
+	for extra > 0 {
+		best := -1
+		for j, i := range active {
+			if !got[j] && (best < 0 || quotas[i] > quotas[active[best]]) {
+				best = j
+			}
+		}
+		alloc[active[best]]++
+		got[best] = true
+		extra--
+	}
+}
@@ -0,0 +1,206 @@
+package rng
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+type regionOrder struct {
+	id     int
+	region string
+}
+
+func ordersByRegion(sizes map[string]int) []regionOrder {
+	var out []regionOrder
+	for _, region := range []string{"eu", "us", "apac"} {
+		for range sizes[region] {
+			out = append(out, regionOrder{id: len(out), region: region})
+		}
+	}
+	return out
+}
+
+func regionOf(o regionOrder) string { return o.region }
+
+func TestStratifiedFixed(t *testing.T) {
+	t.Run("empty slice", func(t *testing.T) {
+		if got := StratifiedFixed(nil, regionOf, 5); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("n per stratum", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 100, "us": 50, "apac": 3})
+		strata := StratifiedFixed(orders, regionOf, 5)
+
+		if len(strata) != 3 {
+			t.Fatalf("expected 3 strata, got %d", len(strata))
+		}
+		want := []struct {
+			key  string
+			size int
+			n    int
+		}{{"eu", 100, 5}, {"us", 50, 5}, {"apac", 3, 3}}
+		for i, w := range want {
+			s := strata[i]
+			if s.Key != w.key || s.Size != w.size || len(s.Sample) != w.n {
+				t.Errorf("stratum %d: got key=%s size=%d n=%d, want %+v", i, s.Key, s.Size, len(s.Sample), w)
+			}
+			if math.Abs(s.InclusionProbability-float64(w.n)/float64(w.size)) > 1e-12 {
+				t.Errorf("stratum %s: unexpected inclusion probability %f", s.Key, s.InclusionProbability)
+			}
+			for _, o := range s.Sample {
+				if o.region != s.Key {
+					t.Errorf("stratum %s contains order from %s", s.Key, o.region)
+				}
+			}
+		}
+	})
+}
+
+func TestStratifiedProportional(t *testing.T) {
+	t.Run("panics for invalid fraction", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic for fraction > 1")
+			}
+		}()
+		StratifiedProportional([]int{1}, func(int) int { return 0 }, 1.5)
+	})
+
+	t.Run("same fraction per stratum", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 1000, "us": 500, "apac": 50})
+		strata := StratifiedProportional(orders, regionOf, 0.02)
+
+		total := 0
+		for _, s := range strata {
+			total += len(s.Sample)
+			if want := float64(s.Size) * 0.02; math.Abs(float64(len(s.Sample))-want) >= 1 {
+				t.Errorf("stratum %s: expected about %f elements, got %d", s.Key, want, len(s.Sample))
+			}
+		}
+		if total != 31 {
+			t.Errorf("expected 31 elements in total, got %d", total)
+		}
+	})
+
+	t.Run("small strata are sampled regardless of order", func(t *testing.T) {
+		in := seqSlice(1000)
+		sampled := make(map[int]int)
+		for range 400 {
+			for _, s := range StratifiedProportional(in, func(v int) int { return v / 10 }, 0.05) {
+				if len(s.Sample) > 0 {
+					sampled[s.Key]++
+				}
+			}
+		}
+		// Each of the 100 strata has a quota of 0.5, so it should be sampled about half the time.
+		for k := range 100 {
+			if sampled[k] < 140 || sampled[k] > 260 {
+				t.Errorf("stratum %d: sampled %d of 400 times, expected about 200", k, sampled[k])
+			}
+		}
+	})
+
+	t.Run("distinct elements", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 10, "us": 10})
+		for _, s := range StratifiedProportional(orders, regionOf, 1) {
+			ids := make([]int, len(s.Sample))
+			for i, o := range s.Sample {
+				ids[i] = o.id
+			}
+			slices.Sort(ids)
+			if len(slices.Compact(ids)) != 10 {
+				t.Errorf("stratum %s: expected 10 distinct orders, got %v", s.Key, ids)
+			}
+		}
+	})
+}
+
+func TestStratifiedNeyman(t *testing.T) {
+	t.Run("allocates by size and standard deviation", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 1000, "us": 1000, "apac": 500})
+		strata := StratifiedNeyman(orders, regionOf, 100, map[string]float64{"eu": 1, "us": 9, "apac": 4})
+
+		// Weights are 1000·1, 1000·3 and 500·2, so the shares are 20%, 60% and 20%.
+		want := map[string]int{"eu": 20, "us": 60, "apac": 20}
+		for _, s := range strata {
+			if len(s.Sample) != want[s.Key] {
+				t.Errorf("stratum %s: expected %d elements, got %d", s.Key, want[s.Key], len(s.Sample))
+			}
+		}
+	})
+
+	t.Run("caps strata at their size", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 1000, "us": 10, "apac": 1000})
+		strata := StratifiedNeyman(orders, regionOf, 100, map[string]float64{"eu": 1, "us": 10000, "apac": 1})
+
+		want := map[string]int{"eu": 45, "us": 10, "apac": 45}
+		for _, s := range strata {
+			if len(s.Sample) != want[s.Key] {
+				t.Errorf("stratum %s: expected %d elements, got %d", s.Key, want[s.Key], len(s.Sample))
+			}
+		}
+	})
+
+	t.Run("zero variances fall back to proportional", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 300, "us": 100})
+		strata := StratifiedNeyman(orders, regionOf, 40, nil)
+		if len(strata[0].Sample) != 30 || len(strata[1].Sample) != 10 {
+			t.Errorf("expected 30 and 10 elements, got %d and %d", len(strata[0].Sample), len(strata[1].Sample))
+		}
+	})
+
+	t.Run("n above population draws everything", func(t *testing.T) {
+		orders := ordersByRegion(map[string]int{"eu": 3, "us": 2})
+		for _, s := range StratifiedNeyman(orders, regionOf, 100, map[string]float64{"eu": 1}) {
+			if len(s.Sample) != s.Size || s.InclusionProbability != 1 {
+				t.Errorf("stratum %s: expected the whole stratum, got %d of %d", s.Key, len(s.Sample), s.Size)
+			}
+		}
+	})
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sizes   []int
+		weights []float64
+		n       int
+		want    []int
+	}{
+		{"exact", []int{10, 10}, []float64{1, 3}, 8, []int{2, 6}},
+		{"negative n", []int{5}, []float64{1}, -1, []int{0}},
+		{"spill to zero weight", []int{2, 5}, []float64{1, 0}, 4, []int{2, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := allocate(tt.sizes, tt.weights, tt.n)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("allocate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("random rounding matches expected shares", func(t *testing.T) {
+		const trials = 30000
+		sizes, weights := []int{10, 10, 10}, []float64{1, 1, 2}
+		sums := make([]int, 3)
+		for range trials {
+			got := allocate(sizes, weights, 10)
+			if got[0]+got[1]+got[2] != 10 || got[0] < 2 || got[0] > 3 || got[2] < 5 || got[2] > 6 {
+				t.Fatalf("allocate() = %v, want shares 2.5, 2.5 and 5 rounded", got)
+			}
+			for i, v := range got {
+				sums[i] += v
+			}
+		}
+		for i, want := range []float64{2.5, 2.5, 5} {
+			if mean := float64(sums[i]) / trials; math.Abs(mean-want) > 0.02 {
+				t.Errorf("group %d: expected mean allocation %f, got %f", i, want, mean)
+			}
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package rng
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sort"
+)
+
+// PickWeighted returns a random element of the slice, chosen with probability proportional
+// to its weight as returned by weight.
+//
+// Weights follow the same rules as Lottery: elements with weight <= 0 are never picked,
+// unless no element has a positive weight, in which case all elements are equally likely.
+// If the slice is empty, it returns the zero value of type E.
+func PickWeighted[E any](in []E, weight func(E) float64) E {
+	if len(in) == 0 {
+		return zeroVal[E]()
+	}
+	cumulative := cumulativeWeights(in, weight)
+	return in[searchCumulative(cumulative)]
+}
+
+// PickNWeighted returns n elements picked independently from the slice with replacement,
+// each chosen with probability proportional to its weight, following the rules of PickWeighted.
+//
+// The weights are computed once, and each pick takes logarithmic time in the length of the slice.
+// Returns nil if n <= 0 or if the slice is empty.
+func PickNWeighted[E any](in []E, n int, weight func(E) float64) []E {
+	if n <= 0 || len(in) == 0 {
+		return nil
+	}
+
+	cumulative := cumulativeWeights(in, weight)
+	out := make([]E, n)
+	for i := range out {
+		out[i] = in[searchCumulative(cumulative)]
+	}
+	return out
+}
+
+// PickNWeightedDistinct returns n distinct elements of the slice picked without replacement.
+//
+// The result matches picking one element at a time with PickWeighted and removing it from the
+// slice before the next pick, and the elements are returned in that pick order. Once every element
+// with a positive weight has been picked, the remaining picks are uniform among the others.
+// Returns nil if n <= 0. Panics if n is greater than the length of the slice.
+func PickNWeightedDistinct[E any](in []E, n int, weight func(E) float64) []E {
+	if n <= 0 {
+		return nil
+	}
+	if n > len(in) {
+		panic("n must be less than the length of the slice")
+	}
+
+	var rest []int
+	picked := WeightedReservoir(func(yield func(int, float64) bool) {
+		for i := range in {
+			w := weight(in[i])
+			if !(w > 0) {
+				rest = append(rest, i)
+				continue
+			}
+			if !yield(i, w) {
+				return
+			}
+		}
+	}, n)
+
+	out := make([]E, 0, n)
+	for _, i := range picked {
+		out = append(out, in[i])
+	}
+	for _, j := range PickNIndices(len(rest), n-len(out)) {
+		out = append(out, in[rest[j]])
+	}
+	return out
+}
+
+// ShuffleWeighted randomly rearranges the elements of the slice in place so that elements with
+// higher weights tend to come first.
+//
+// The resulting order matches picking every element with PickNWeightedDistinct: the first element
+// is chosen with probability proportional to its weight, the second among the remaining ones, and
+// so on. Elements with weight <= 0 end up last in uniformly random order.
+func ShuffleWeighted[E any](in []E, weight func(E) float64) {
+	if len(in) <= 1 {
+		return
+	}
+
+	keys := make([]float64, len(in))
+	for i := range in {
+		if w := weight(in[i]); w > 0 {
+			keys[i] = math.Log(unitOpen()) / w
+		} else {
+			keys[i] = math.Inf(-1)
+		}
+	}
+
+	order := make([]int, len(in))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int {
+		return cmp.Compare(keys[b], keys[a])
+	})
+
+	// Elements without a positive weight all share the lowest key, so shuffle them separately.
+	tail := len(order)
+	for tail > 0 && math.IsInf(keys[order[tail-1]], -1) {
+		tail--
+	}
+	Shuffle(order[tail:])
+
+	out := make([]E, len(in))
+	for i, j := range order {
+		out[i] = in[j]
+	}
+	copy(in, out)
+}
+
+// cumulativeWeights returns the running totals of the positive weights of the slice, or of equal
+// weights if no weight is positive.
+func cumulativeWeights[E any](in []E, weight func(E) float64) []float64 {
+	cumulative := make([]float64, len(in))
+	total := 0.0
+	for i := range in {
+		if w := weight(in[i]); w > 0 {
+			total += w
+		}
+		cumulative[i] = total
+	}
+
+	if total == 0 {
+		for i := range cumulative {
+			cumulative[i] = float64(i + 1)
+		}
+	}
+	return cumulative
+}
+
+// searchCumulative returns a random index, chosen with probability proportional to the
+// increase of the running total at that index.
+func searchCumulative(cumulative []float64) int {
+	v := r.Float64() * cumulative[len(cumulative)-1]
+	i := sort.Search(len(cumulative), func(i int) bool { return cumulative[i] > v })
+	return min(i, len(cumulative)-1)
+}
@@ -0,0 +1,169 @@
+package rng
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPickWeighted(t *testing.T) {
+	weight := func(s string) float64 {
+		return map[string]float64{"a": 1, "b": 3, "zero": 0, "negative": -2}[s]
+	}
+
+	t.Run("empty slice returns zero value", func(t *testing.T) {
+		if got := PickWeighted(nil, weight); got != "" {
+			t.Errorf("expected empty string, got %q", got)
+		}
+	})
+
+	t.Run("follows weights", func(t *testing.T) {
+		counts := make(map[string]int)
+		for range 20000 {
+			counts[PickWeighted([]string{"zero", "a", "negative", "b"}, weight)]++
+		}
+		if counts["zero"] != 0 || counts["negative"] != 0 {
+			t.Errorf("expected non-positive weights never to be picked, got %v", counts)
+		}
+		if ratio := float64(counts["b"]) / 20000; math.Abs(ratio-0.75) > 0.02 {
+			t.Errorf("expected about 75%% b, got %f", ratio)
+		}
+	})
+
+	t.Run("falls back to uniform", func(t *testing.T) {
+		counts := make(map[string]int)
+		for range 20000 {
+			counts[PickWeighted([]string{"zero", "negative"}, weight)]++
+		}
+		if ratio := float64(counts["zero"]) / 20000; math.Abs(ratio-0.5) > 0.02 {
+			t.Errorf("expected about 50%% zero, got %f", ratio)
+		}
+	})
+}
+
+func TestPickNWeighted(t *testing.T) {
+	weight := func(v int) float64 { return float64(v) }
+
+	t.Run("returns nil for n <= 0 or empty slice", func(t *testing.T) {
+		if got := PickNWeighted([]int{1}, 0, weight); got != nil {
+			t.Errorf("expected nil for n=0, got %v", got)
+		}
+		if got := PickNWeighted(nil, 3, weight); got != nil {
+			t.Errorf("expected nil for empty slice, got %v", got)
+		}
+	})
+
+	t.Run("picks with replacement by weight", func(t *testing.T) {
+		got := PickNWeighted([]int{0, 1, 2, 3, 4}, 100000, weight)
+		if len(got) != 100000 {
+			t.Fatalf("expected 100000 picks, got %d", len(got))
+		}
+
+		counts := make(map[int]int)
+		for _, v := range got {
+			counts[v]++
+		}
+		for v := range 5 {
+			want := float64(v) / 10
+			if ratio := float64(counts[v]) / 100000; math.Abs(ratio-want) > 0.01 {
+				t.Errorf("value %d: expected share %f, got %f", v, want, ratio)
+			}
+		}
+	})
+}
+
+func TestPickNWeightedDistinct(t *testing.T) {
+	weights := map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4}
+	weight := func(s string) float64 { return weights[s] }
+	in := []string{"a", "b", "zero1", "c", "d", "zero2"}
+
+	t.Run("returns nil for n <= 0", func(t *testing.T) {
+		if got := PickNWeightedDistinct(in, 0, weight); got != nil {
+			t.Errorf("expected nil, got %v", got)
+		}
+	})
+
+	t.Run("panics when n > slice length", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("expected panic when n > slice length")
+			}
+		}()
+		PickNWeightedDistinct(in, 7, weight)
+	})
+
+	t.Run("successive sampling probabilities", func(t *testing.T) {
+		// Exact probability of each ordered pair when picking one at a time without replacement.
+		iterations := 50000
+		counts := make(map[string]int)
+		for range iterations {
+			got := PickNWeightedDistinct(in, 2, weight)
+			counts[got[0]+got[1]]++
+		}
+
+		for first, w1 := range weights {
+			for second, w2 := range weights {
+				if first == second {
+					continue
+				}
+				want := w1 / 10 * w2 / (10 - w1)
+				if got := float64(counts[first+second]) / float64(iterations); math.Abs(got-want) > 0.01 {
+					t.Errorf("pair %s%s: expected %f, got %f", first, second, want, got)
+				}
+			}
+		}
+		for pair := range counts {
+			if strings.Contains(pair, "zero") {
+				t.Errorf("unexpected zero-weight pick %s", pair)
+			}
+		}
+	})
+
+	t.Run("zero weights fill the remainder", func(t *testing.T) {
+		got := PickNWeightedDistinct(in, 6, weight)
+		slices.Sort(got[4:])
+		if !slices.Equal(got[4:], []string{"zero1", "zero2"}) {
+			t.Errorf("expected zero weights last, got %v", got)
+		}
+		sorted := slices.Sorted(slices.Values(got))
+		if !slices.Equal(sorted, []string{"a", "b", "c", "d", "zero1", "zero2"}) {
+			t.Errorf("expected every element once, got %v", got)
+		}
+	})
+}
+
+func TestShuffleWeighted(t *testing.T) {
+	weights := map[string]float64{"a": 1, "b": 2, "c": 3}
+	weight := func(s string) float64 { return weights[s] }
+
+	t.Run("empty and single element", func(t *testing.T) {
+		ShuffleWeighted(nil, weight)
+		one := []string{"a"}
+		ShuffleWeighted(one, weight)
+		if one[0] != "a" {
+			t.Errorf("expected [a], got %v", one)
+		}
+	})
+
+	t.Run("every ordering has its successive sampling probability", func(t *testing.T) {
+		iterations := 60000
+		counts := make(map[string]int)
+		for range iterations {
+			s := []string{"a", "b", "c", "x", "y"}
+			ShuffleWeighted(s, weight)
+			counts[strings.Join(s, "")]++
+		}
+
+		perms := []string{"abc", "acb", "bac", "bca", "cab", "cba"}
+		for _, p := range perms {
+			w1, w2 := weights[p[:1]], weights[p[1:2]]
+			want := w1 / 6 * w2 / (6 - w1) / 2 // x and y come last in either order
+			for _, tail := range []string{"xy", "yx"} {
+				if got := float64(counts[p+tail]) / float64(iterations); math.Abs(got-want) > 0.01 {
+					t.Errorf("order %s: expected %f, got %f", p+tail, want, got)
+				}
+			}
+		}
+	})
+}